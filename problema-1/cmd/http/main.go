@@ -6,12 +6,14 @@ import (
 	"os"
 
 	http_adapter "github.com/do-prueba-tecnica/problema-1/internal/infra/http"
+	// driver de postgres para el comando migrate
+	_ "github.com/lib/pq"
 )
 
 func main() {
 	ctx := context.Background()
 	if err := http_adapter.Run(ctx, os.Getenv, os.Stdin, os.Stdout, os.Stderr, os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, "%s\n", err)
+		fmt.Fprintf(os.Stderr, "%s\n", http_adapter.ErrorMessage(err, http_adapter.LangFromEnv(os.Getenv)))
 		os.Exit(1)
 	}
 }
@@ -1,18 +1,39 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 type App struct {
 	stderr io.Writer
 	stdout io.Writer
 	logger *slog.Logger
+
+	// errorCounts guarda un *atomic.Uint64 por cada razon de error, asi el App{} vacio es usable
+	errorCounts sync.Map
+}
+
+func (app *App) countError(err *Error) {
+	counter, _ := app.errorCounts.LoadOrStore(err.Code, &atomic.Uint64{})
+	counter.(*atomic.Uint64).Add(1)
+}
+
+// ErrorCounts retorna la cantidad de conversiones fallidas por codigo de error.
+func (app *App) ErrorCounts() map[string]uint64 {
+	counts := map[string]uint64{}
+	app.errorCounts.Range(func(key, value any) bool {
+		counts[key.(string)] = value.(*atomic.Uint64).Load()
+		return true
+	})
+	return counts
 }
 
 func NewApp(
@@ -38,9 +59,17 @@ func NewApp(
 
 var patentRX = regexp.MustCompile(`^[A-Za-z]{4}[0-9]{3}$`)
 
-func (app *App) IDtoPatent(id uint) (error, string) {
-	if id < 1 || id > 456976000 { // 456976000 = ZZZZ999
-		return fmt.Errorf("id to patent: invalid ID range"), ""
+// MaxID es el id de la ultima patente, ZZZZ999.
+const MaxID = 456976000
+
+// PatentFromID retorna la patente que corresponde al id, el primer id es 1 (AAAA000).
+func (app *App) PatentFromID(ctx context.Context, id uint) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if id < 1 || id > MaxID {
+		app.countError(ErrInvalidRange)
+		return "", fmt.Errorf("id to patent: %w", ErrInvalidRange)
 	}
 
 	// restamos 1 del id para que comienze en 0
@@ -62,15 +91,21 @@ func (app *App) IDtoPatent(id uint) (error, string) {
 
 	// Formateamos la patent
 	patent := fmt.Sprintf("%s%03d", string(patentText), patentNumbers)
-	return nil, patent
+	return patent, nil
 }
 
-func (app *App) PatentToID(patent string) (error, uint) {
+// IDFromPatent retorna el id de la patente, acepta letras en minuscula.
+func (app *App) IDFromPatent(ctx context.Context, patent string) (uint, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	if patent == "" {
-		return fmt.Errorf("patent to id: patent cannot be empty string"), 0
+		app.countError(ErrEmpty)
+		return 0, fmt.Errorf("patent to id: %w", ErrEmpty)
 	}
 	if !patentRX.MatchString(patent) {
-		return fmt.Errorf("patent to id: patent string does not match correct format"), 0
+		app.countError(ErrBadFormat)
+		return 0, fmt.Errorf("patent to id: %w", ErrBadFormat)
 	}
 
 	// pre calculamos las potencias en base a 26 para poder subir el nivel de cada letra de acuerdo
@@ -90,13 +125,14 @@ func (app *App) PatentToID(patent string) (error, uint) {
 	patentNumbers := patent[4:]
 	idNumbers, err := strconv.Atoi(patentNumbers)
 	if err != nil {
-		return fmt.Errorf(
-			"patent to id: chars in numbers position in patent string failed int conversion: %w",
-			err,
-		), 0
+		app.countError(ErrBadFormat)
+		return 0, fmt.Errorf(
+			"patent to id: %w: chars in numbers position in patent string failed int conversion: %w",
+			ErrBadFormat, err,
+		)
 	}
 
 	// Combinamos los valores, como la primera patente es 1, debemos sumar 1
 	id := idText*1000 + uint(idNumbers+1)
-	return nil, id
+	return id, nil
 }
@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -79,3 +81,65 @@ func TestIDtoPatent(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCodes(t *testing.T) {
+	app := &App{}
+
+	err, _ := app.IDtoPatent(0)
+	if !errors.Is(err, ErrInvalidRange) || ErrorCode(err) != CodeInvalidRange {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+
+	tests := []struct {
+		patente  string
+		expected error
+	}{
+		{"", ErrEmpty},
+		{"AAAA", ErrBadFormat},
+		{"AAAÑ889", ErrBadFormat},
+	}
+	for _, tt := range tests {
+		err, _ := app.PatentToID(tt.patente)
+		if !errors.Is(err, tt.expected) {
+			t.Errorf("Expected %v for %q, got %v", tt.expected, tt.patente, err)
+		}
+	}
+
+	counts := app.ErrorCounts()
+	if counts[CodeBadFormat] != 2 || counts[CodeEmpty] != 1 || counts[CodeInvalidRange] != 1 {
+		t.Errorf("Unexpected error counts %v", counts)
+	}
+}
+
+func TestServiceV1(t *testing.T) {
+	var service ServiceV1 = &App{}
+	ctx := context.Background()
+
+	tests := []struct {
+		id     uint
+		patent string
+	}{
+		{1, "AAAA000"},
+		{1001, "AAAB000"},
+		{MaxID, "ZZZZ999"},
+	}
+	for _, tt := range tests {
+		patent, err := service.PatentFromID(ctx, tt.id)
+		if err != nil || patent != tt.patent {
+			t.Errorf("PatentFromID(%d) = %q, %v, expected %q", tt.id, patent, err, tt.patent)
+		}
+		id, err := service.IDFromPatent(ctx, tt.patent)
+		if err != nil || id != tt.id {
+			t.Errorf("IDFromPatent(%q) = %d, %v, expected %d", tt.patent, id, err, tt.id)
+		}
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := service.PatentFromID(canceled, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := service.IDFromPatent(canceled, "AAAA000"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package app
+
+import "errors"
+
+// Error es un error de dominio con un codigo estable que los adapters pueden exponer a los clientes
+// sin depender del texto del mensaje.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Codigos de error de las conversiones, tambien se usan como label en las metricas.
+const (
+	CodeInvalidRange = "invalid_range"
+	CodeEmpty        = "empty"
+	CodeBadFormat    = "bad_format"
+)
+
+var (
+	ErrInvalidRange = &Error{Code: CodeInvalidRange, Message: "invalid ID range"}
+	ErrEmpty        = &Error{Code: CodeEmpty, Message: "patent cannot be empty string"}
+	ErrBadFormat    = &Error{Code: CodeBadFormat, Message: "patent string does not match correct format"}
+)
+
+// ErrorCode retorna el codigo del error de dominio envuelto en err, o "" si no hay ninguno.
+func ErrorCode(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
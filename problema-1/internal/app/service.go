@@ -0,0 +1,30 @@
+package app
+
+import "context"
+
+// ServiceV1 es el contrato del servicio de conversiones del que dependen los adapters. Los cambios
+// incompatibles van en una nueva version de la interfaz para que ambas puedan convivir.
+type ServiceV1 interface {
+	// PatentFromID retorna la patente del id, los ids validos van de 1 a MaxID.
+	PatentFromID(ctx context.Context, id uint) (string, error)
+	// IDFromPatent retorna el id de una patente con formato LLLLNNN.
+	IDFromPatent(ctx context.Context, patent string) (uint, error)
+}
+
+var _ ServiceV1 = (*App)(nil)
+
+// IDtoPatent es la version anterior de PatentFromID.
+//
+// Deprecated: usar PatentFromID, que retorna (valor, error) y recibe un context.
+func (app *App) IDtoPatent(id uint) (error, string) {
+	patent, err := app.PatentFromID(context.Background(), id)
+	return err, patent
+}
+
+// PatentToID es la version anterior de IDFromPatent.
+//
+// Deprecated: usar IDFromPatent, que retorna (valor, error) y recibe un context.
+func (app *App) PatentToID(patent string) (error, uint) {
+	id, err := app.IDFromPatent(context.Background(), patent)
+	return err, id
+}
@@ -0,0 +1,276 @@
+// Package apikey maneja las api keys del servicio. Las keys se guardan en un archivo JSON local solo
+// como hash sha256, el secreto se muestra una unica vez al crearla.
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScopeAdmin da acceso a todos los scopes.
+const ScopeAdmin = "admin"
+
+// Prefix es el prefijo de todas las keys, permite distinguirlas de otros tokens.
+const Prefix = "pk_"
+
+var (
+	ErrInvalidKey = errors.New("apikey: invalid key")
+	ErrExpired    = errors.New("apikey: key expired")
+	ErrRevoked    = errors.New("apikey: key revoked")
+	ErrNotFound   = errors.New("apikey: key not found")
+)
+
+type Key struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	Hash      string    `json:"hash,omitempty"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	// ExpiresAt en nil significa que la key no expira.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// HasScope reporta si la key tiene el scope o es admin.
+func (k Key) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope) || slices.Contains(k.Scopes, ScopeAdmin)
+}
+
+// Status retorna active, expired o revoked para mostrar en el listado.
+func (k Key) Status(now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return "revoked"
+	case k.ExpiresAt != nil && now.After(*k.ExpiresAt):
+		return "expired"
+	}
+	return "active"
+}
+
+type file struct {
+	Keys []Key `json:"keys"`
+}
+
+// Store es el archivo de keys. El archivo se vuelve a leer a lo mas una vez por segundo al
+// autenticar, asi los cambios del comando keys (por ejemplo revocar una key) aplican sin reiniciar.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	keys    map[string]Key
+	checked time.Time
+}
+
+// Open carga el archivo de keys, si no existe el store parte vacio y se crea al guardar.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, keys: map[string]Key{}}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) load() error {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.keys = map[string]Key{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("apikey: reading %q: %w", s.path, err)
+	}
+	var f file
+	if len(content) > 0 {
+		if err := json.Unmarshal(content, &f); err != nil {
+			return fmt.Errorf("apikey: decoding %q: %w", s.path, err)
+		}
+	}
+	keys := make(map[string]Key, len(f.Keys))
+	for _, key := range f.Keys {
+		keys[key.ID] = key
+	}
+	s.keys = keys
+	return nil
+}
+
+func (s *Store) reload() error {
+	if time.Since(s.checked) < time.Second {
+		return nil
+	}
+	s.checked = time.Now()
+	return s.load()
+}
+
+// save escribe a un archivo temporal y lo renombra para que un lector nunca vea el archivo a medias.
+func (s *Store) save() error {
+	f := file{Keys: s.list()}
+	content, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("apikey: encoding keys: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".keys-*")
+	if err != nil {
+		return fmt.Errorf("apikey: writing %q: %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("apikey: writing %q: %w", s.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("apikey: writing %q: %w", s.path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return fmt.Errorf("apikey: writing %q: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("apikey: writing %q: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *Store) list() []Key {
+	keys := make([]Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt) ||
+			keys[i].CreatedAt.Equal(keys[j].CreatedAt) && keys[i].ID < keys[j].ID
+	})
+	return keys
+}
+
+// List retorna las keys ordenadas por fecha de creacion.
+func (s *Store) List() []Key {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+// Create genera una key nueva y retorna el secreto que debe entregarse al cliente, el store solo
+// guarda su hash. Con expiresAt en cero la key no expira.
+func (s *Store) Create(label string, scopes []string, expiresAt time.Time) (string, Key, error) {
+	if strings.TrimSpace(label) == "" {
+		return "", Key{}, errors.New("apikey: label cannot be empty")
+	}
+	if len(scopes) == 0 {
+		return "", Key{}, errors.New("apikey: at least one scope is required")
+	}
+
+	id, err := randomHex(6)
+	if err != nil {
+		return "", Key{}, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", Key{}, err
+	}
+	token := Prefix + id + "_" + secret
+
+	key := Key{
+		ID:        id,
+		Label:     label,
+		Hash:      hash(token),
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+	if !expiresAt.IsZero() {
+		expiresAt = expiresAt.UTC()
+		key.ExpiresAt = &expiresAt
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return "", Key{}, err
+	}
+	s.keys[id] = key
+	if err := s.save(); err != nil {
+		return "", Key{}, err
+	}
+	return token, key, nil
+}
+
+// Revoke marca la key como revocada, se mantiene en el archivo para auditoria.
+func (s *Store) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	key, ok := s.keys[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if key.RevokedAt == nil {
+		now := time.Now().UTC()
+		key.RevokedAt = &now
+		s.keys[id] = key
+	}
+	return s.save()
+}
+
+// Authenticate busca la key del token y verifica su hash, expiracion y revocacion.
+func (s *Store) Authenticate(token string, now time.Time) (Key, error) {
+	id, _, ok := parse(token)
+	if !ok {
+		return Key{}, ErrInvalidKey
+	}
+
+	s.mu.Lock()
+	if err := s.reload(); err != nil {
+		s.mu.Unlock()
+		return Key{}, err
+	}
+	key, found := s.keys[id]
+	s.mu.Unlock()
+
+	if !found || subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hash(token))) != 1 {
+		return Key{}, ErrInvalidKey
+	}
+	if key.RevokedAt != nil {
+		return Key{}, ErrRevoked
+	}
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return Key{}, ErrExpired
+	}
+	return key, nil
+}
+
+func parse(token string) (id string, secret string, ok bool) {
+	rest, found := strings.CutPrefix(token, Prefix)
+	if !found {
+		return "", "", false
+	}
+	id, secret, found = strings.Cut(rest, "_")
+	if !found || id == "" || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("apikey: generating random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
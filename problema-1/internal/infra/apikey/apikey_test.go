@@ -0,0 +1,98 @@
+package apikey
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	token, key, err := store.Create("kiosko", []string{"convert:read"}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expiredToken, _, err := store.Create("temporal", []string{"convert:read"}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(string(content), token) {
+		t.Errorf("Key file must not contain the plain token")
+	}
+
+	tampered := token[:len(token)-1] + "0"
+	if tampered == token {
+		tampered = token[:len(token)-1] + "1"
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name     string
+		token    string
+		now      time.Time
+		expected error
+	}{
+		{"valid key", token, now, nil},
+		{"unknown format", "secret", now, ErrInvalidKey},
+		{"wrong secret", key.ID + "x", now, ErrInvalidKey},
+		{"tampered secret", tampered, now, ErrInvalidKey},
+		{"not expired yet", expiredToken, now, nil},
+		{"expired", expiredToken, now.Add(2 * time.Hour), ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Authenticate(tt.token, tt.now)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected error %v, got %v", tt.expected, err)
+			}
+			if err == nil && !got.HasScope("convert:read") {
+				t.Errorf("Expected key with scope convert:read, got %v", got.Scopes)
+			}
+		})
+	}
+
+	// otro proceso (el comando keys revoke) revoca la key sobre el mismo archivo
+	other, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := other.Revoke(key.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := other.Revoke("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	time.Sleep(time.Second)
+	if _, err := store.Authenticate(token, time.Now()); !errors.Is(err, ErrRevoked) {
+		t.Errorf("Expected ErrRevoked after reload, got %v", err)
+	}
+
+	keys := store.List()
+	if len(keys) != 2 || keys[0].Label != "kiosko" || keys[0].Status(time.Now()) != "revoked" {
+		t.Errorf("Unexpected keys %+v", keys)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	admin := Key{Scopes: []string{ScopeAdmin}}
+	reader := Key{Scopes: []string{"convert:read"}}
+	if !admin.HasScope("convert:read") || !admin.HasScope("anything") {
+		t.Errorf("Admin key must have every scope")
+	}
+	if reader.HasScope(ScopeAdmin) {
+		t.Errorf("Reader key must not have admin scope")
+	}
+}
@@ -0,0 +1,87 @@
+// Package certs carga los certificados TLS del servidor. El par certificado/llave se vuelve a leer
+// cuando cambian los archivos, asi se pueden rotar sin reiniciar el proceso.
+package certs
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader entrega el certificado actual en GetCertificate. Los archivos se revisan a lo mas una vez
+// por segundo y si al recargarlos fallan (por ejemplo a medio copiar) se sigue usando el anterior.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	// OnError recibe los errores de recarga, puede ser nil.
+	OnError func(error)
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certPEM []byte
+	keyPEM  []byte
+	checked time.Time
+}
+
+// NewReloader carga el par certificado/llave, falla si no es valido.
+func NewReloader(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	r.checked = time.Now()
+	return r, nil
+}
+
+func (r *Reloader) reload() error {
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return fmt.Errorf("certs: reading %q: %w", r.certFile, err)
+	}
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("certs: reading %q: %w", r.keyFile, err)
+	}
+	// se compara el contenido y no el mtime, que en algunos sistemas tiene resolucion de segundos
+	if bytes.Equal(certPEM, r.certPEM) && bytes.Equal(keyPEM, r.keyPEM) {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("certs: loading %q and %q: %w", r.certFile, r.keyFile, err)
+	}
+	r.cert, r.certPEM, r.keyPEM = &cert, certPEM, keyPEM
+	return nil
+}
+
+// GetCertificate sirve como tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) >= time.Second {
+		r.checked = time.Now()
+		if err := r.reload(); err != nil && r.OnError != nil {
+			r.OnError(err)
+		}
+	}
+	return r.cert, nil
+}
+
+// LoadCAPool lee un bundle PEM con los certificados de las CA de clientes.
+func LoadCAPool(path string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("certs: reading %q: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, errors.New("certs: no certificates found in " + path)
+	}
+	return pool, nil
+}
@@ -0,0 +1,120 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePair(t *testing.T, dir string, cert tls.Certificate) (string, string) {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(certFile, certPEM, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestSelfSigned(t *testing.T) {
+	now := time.Now()
+	cert, err := SelfSigned([]string{"localhost", "127.0.0.1"}, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert.Leaf)
+	for _, host := range []string{"localhost", "127.0.0.1"} {
+		if _, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pool}); err != nil {
+			t.Errorf("Expected certificate valid for %s: %v", host, err)
+		}
+	}
+	// un servidor de desarrollo que queda corriendo dias sigue aceptando handshakes
+	later := now.Add(30 * 24 * time.Hour)
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{DNSName: "localhost", Roots: pool, CurrentTime: later}); err != nil {
+		t.Errorf("Expected certificate valid after a month: %v", err)
+	}
+	if len(Fingerprint(cert)) != 64 {
+		t.Errorf("Expected sha256 hex fingerprint, got %q", Fingerprint(cert))
+	}
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	first, err := SelfSigned([]string{"localhost"}, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	certFile, keyFile := writePair(t, dir, first)
+
+	r, err := NewReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var reloadErr error
+	r.OnError = func(err error) { reloadErr = err }
+
+	current := func() string {
+		cert, err := r.GetCertificate(nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return Fingerprint(*cert)
+	}
+	if current() != Fingerprint(first) {
+		t.Fatalf("Expected initial certificate")
+	}
+
+	// un par invalido no reemplaza al certificado actual
+	if err := os.WriteFile(keyFile, []byte("nope"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	time.Sleep(time.Second)
+	if current() != Fingerprint(first) || reloadErr == nil {
+		t.Errorf("Expected old certificate and a reload error")
+	}
+
+	second, err := SelfSigned([]string{"localhost"}, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	writePair(t, dir, second)
+	time.Sleep(time.Second)
+	if current() != Fingerprint(second) {
+		t.Errorf("Expected rotated certificate")
+	}
+
+	if _, err := NewReloader(certFile, filepath.Join(dir, "missing.pem")); err == nil {
+		t.Errorf("Expected error for missing key")
+	}
+}
+
+func TestLoadCAPool(t *testing.T) {
+	dir := t.TempDir()
+	cert, err := SelfSigned([]string{"localhost"}, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	certFile, keyFile := writePair(t, dir, cert)
+
+	if _, err := LoadCAPool(certFile); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := LoadCAPool(keyFile); err == nil {
+		t.Errorf("Expected error for a file without certificates")
+	}
+}
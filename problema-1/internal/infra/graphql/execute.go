@@ -0,0 +1,672 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Request es el body de un request GraphQL sobre HTTP.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Limits acota las queries antes de ejecutarlas, 0 no limita. La profundidad de los campos de la
+// raiz es 1 y la complejidad suma 1 por campo, multiplicando los subcampos de las listas por su
+// Multiplier.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// Response es el resultado de una query. Si la query no es valida no se ejecuta y Data es nil, si se
+// ejecuto Data esta presente aunque haya errores en algunos campos.
+type Response struct {
+	Errors []*Error `json:"errors,omitempty"`
+	Data   any      `json:"data,omitempty"`
+}
+
+// Execute parsea, valida y ejecuta la query.
+func (s *Schema) Execute(ctx context.Context, req Request, limits Limits) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{toError(err)}}
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{toError(err)}}
+	}
+	vars, errs := coerceVariables(op, req.Variables)
+	if len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+
+	v := &validator{doc: doc, vars: vars, limits: limits, visiting: map[string]bool{}, fragments: map[fragmentKey]int{}}
+	v.selectionSet(s.Query, op.selectionSet, 1)
+	if v.tooComplex {
+		v.errorf(op.loc, "Query complexity exceeds the limit of %d.", limits.MaxComplexity)
+	}
+	if len(v.errs) > 0 {
+		return &Response{Errors: v.errs}
+	}
+
+	e := &executor{ctx: ctx, doc: doc, vars: vars}
+	data, ok := e.selectionSet(s.Query, nil, op.selectionSet, nil)
+	resp := &Response{Errors: e.errs, Data: data}
+	if !ok {
+		resp.Data = json.RawMessage("null")
+	}
+	return resp
+}
+
+func toError(err error) *Error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		return gqlErr
+	}
+	return &Error{Message: err.Error()}
+}
+
+func (doc *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(doc.operations) > 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations."}
+		}
+		return doc.operations[0], nil
+	}
+	for _, op := range doc.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q.", name)}
+}
+
+// inputTypes son los tipos que pueden declarar las variables.
+var inputTypes = map[string]*Scalar{
+	Int.Name:     Int,
+	Float.Name:   Float,
+	String.Name:  String,
+	Boolean.Name: Boolean,
+}
+
+func (t *typeRef) schemaType() (Type, bool) {
+	var base Type
+	if t.list != nil {
+		of, ok := t.list.schemaType()
+		if !ok {
+			return nil, false
+		}
+		base = &List{Of: of}
+	} else {
+		scalar, ok := inputTypes[t.name]
+		if !ok {
+			return nil, false
+		}
+		base = scalar
+	}
+	if t.nonNull {
+		return &NonNull{Of: base}, true
+	}
+	return base, true
+}
+
+// coerceVariables valida las variables del request contra las declaradas en la operacion, las
+// variables sin valor ni default no estan en el resultado.
+func coerceVariables(op *operation, values map[string]any) (map[string]any, []*Error) {
+	vars := map[string]any{}
+	var errs []*Error
+	for _, def := range op.variables {
+		typ, ok := def.typ.schemaType()
+		if !ok {
+			errs = append(errs, &Error{
+				Message:   fmt.Sprintf("Variable \"$%s\" cannot be of type %q.", def.name, def.typ),
+				Locations: []Location{def.loc},
+			})
+			continue
+		}
+		raw, present := values[def.name]
+		if !present && def.defaultValue != nil {
+			raw, present = literal(def.defaultValue, nil), true
+		}
+		if !present {
+			if _, nonNull := typ.(*NonNull); nonNull {
+				errs = append(errs, &Error{
+					Message:   fmt.Sprintf("Variable \"$%s\" of required type %q was not provided.", def.name, def.typ),
+					Locations: []Location{def.loc},
+				})
+			}
+			continue
+		}
+		value, err := coerceInput(typ, raw)
+		if err != nil {
+			errs = append(errs, &Error{
+				Message:   fmt.Sprintf("Variable \"$%s\" got invalid value: %s.", def.name, err),
+				Locations: []Location{def.loc},
+			})
+			continue
+		}
+		vars[def.name] = value
+	}
+	return vars, errs
+}
+
+// literal convierte un valor de la query a los tipos que usa coerceInput, las variables sin valor
+// son nil.
+func literal(v *value, vars map[string]any) any {
+	switch v.kind {
+	case valueVariable:
+		return vars[v.raw]
+	case valueInt:
+		i, err := strconv.ParseInt(v.raw, 10, 64)
+		if err != nil {
+			// fuera de rango, coerceInput lo rechaza como Int
+			f, _ := strconv.ParseFloat(v.raw, 64)
+			return f
+		}
+		return i
+	case valueFloat:
+		f, _ := strconv.ParseFloat(v.raw, 64)
+		return f
+	case valueString:
+		return v.raw
+	case valueBoolean:
+		return v.raw == "true"
+	case valueList:
+		list := make([]any, len(v.list))
+		for i, item := range v.list {
+			list[i] = literal(item, vars)
+		}
+		return list
+	case valueObject:
+		obj := map[string]any{}
+		for _, f := range v.fields {
+			obj[f.name] = literal(f.value, vars)
+		}
+		return obj
+	case valueEnum:
+		return enumValue(v.raw)
+	}
+	return nil
+}
+
+// enumValue es un enum literal, no hay enums en los tipos de entrada asi que siempre es invalido.
+type enumValue string
+
+func coerceInput(t Type, v any) (any, error) {
+	switch t := t.(type) {
+	case *NonNull:
+		if v == nil {
+			return nil, fmt.Errorf("expected non-null %s", t)
+		}
+		return coerceInput(t.Of, v)
+	case *List:
+		if v == nil {
+			return nil, nil
+		}
+		items, ok := v.([]any)
+		if !ok {
+			// un valor suelto es una lista de un elemento
+			item, err := coerceInput(t.Of, v)
+			if err != nil {
+				return nil, err
+			}
+			return []any{item}, nil
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			value, err := coerceInput(t.Of, item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	case *Scalar:
+		if v == nil {
+			return nil, nil
+		}
+		value, ok := t.parse(v)
+		if !ok {
+			return nil, fmt.Errorf("%s cannot represent %s", t.Name, describe(v))
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+func describe(v any) string {
+	if enum, ok := v.(enumValue); ok {
+		return string(enum)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// coerceArgs valida los argumentos del campo, los que no se pasan toman su default.
+func coerceArgs(def *Field, f *field, vars map[string]any) (map[string]any, error) {
+	args := map[string]any{}
+	for _, node := range f.arguments {
+		known := false
+		for _, a := range def.Args {
+			known = known || a.Name == node.name
+		}
+		if !known {
+			return nil, fmt.Errorf("Unknown argument %q on field %q.", node.name, def.Name)
+		}
+	}
+	for _, a := range def.Args {
+		var node *argument
+		for _, n := range f.arguments {
+			if n.name == a.Name {
+				node = n
+			}
+		}
+		var raw any
+		present := node != nil
+		if present && node.value.kind == valueVariable {
+			raw, present = vars[node.value.raw]
+		} else if present {
+			raw = literal(node.value, vars)
+		}
+		if !present {
+			if a.Default != nil {
+				args[a.Name] = a.Default
+				continue
+			}
+			if _, nonNull := a.Type.(*NonNull); nonNull {
+				return nil, fmt.Errorf("Field %q argument %q of type %q is required.", def.Name, a.Name, a.Type)
+			}
+			continue
+		}
+		value, err := coerceInput(a.Type, raw)
+		if err != nil {
+			return nil, fmt.Errorf("Argument %q has invalid value: %s.", a.Name, err)
+		}
+		args[a.Name] = value
+	}
+	return args, nil
+}
+
+// included evalua @skip e @include, cualquier otra directiva es un error.
+func included(dirs []*directive, vars map[string]any) (bool, error) {
+	for _, d := range dirs {
+		if d.name != "skip" && d.name != "include" {
+			return false, fmt.Errorf("Unknown directive \"@%s\".", d.name)
+		}
+		if len(d.arguments) != 1 || d.arguments[0].name != "if" {
+			return false, fmt.Errorf("Directive \"@%s\" requires a single argument \"if\".", d.name)
+		}
+		value, err := coerceInput(&NonNull{Of: Boolean}, literal(d.arguments[0].value, vars))
+		if err != nil {
+			return false, fmt.Errorf("Directive \"@%s\" argument \"if\" has invalid value: %s.", d.name, err)
+		}
+		if value.(bool) == (d.name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// validator recorre la query antes de ejecutarla, verificando campos, argumentos y fragments contra
+// el schema y calculando la profundidad y la complejidad. La complejidad de cada fragment se calcula
+// una vez por profundidad, sin eso una cadena de fragments que se expanden dos veces cada uno
+// duplica el recorrido por cada fragment.
+type validator struct {
+	doc       *document
+	vars      map[string]any
+	limits    Limits
+	errs      []*Error
+	visiting  map[string]bool
+	fragments map[fragmentKey]int
+	tooDeep   bool
+	// tooComplex corta el recorrido apenas una seleccion pasa MaxComplexity. Es conservador: una
+	// seleccion que pasa el limite dentro de una lista vacia tambien rechaza la query.
+	tooComplex bool
+}
+
+type fragmentKey struct {
+	name  string
+	depth int
+}
+
+func (v *validator) errorf(loc Location, format string, args ...any) {
+	v.errs = append(v.errs, &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}})
+}
+
+// selectionSet valida las selecciones sobre obj y retorna su complejidad.
+func (v *validator) selectionSet(obj *Object, set []selection, depth int) int {
+	if v.limits.MaxDepth > 0 && depth > v.limits.MaxDepth {
+		if !v.tooDeep {
+			v.tooDeep = true
+			v.errorf(set[0].location(), "Query depth exceeds the limit of %d.", v.limits.MaxDepth)
+		}
+		return 0
+	}
+	complexity := 0
+	for _, sel := range set {
+		if v.tooComplex {
+			return complexity
+		}
+		switch sel := sel.(type) {
+		case *field:
+			complexity = addCapped(complexity, v.field(obj, sel, depth))
+		case *fragmentSpread:
+			if ok, err := included(sel.directives, v.vars); err != nil || !ok {
+				if err != nil {
+					v.errorf(sel.loc, "%s", err)
+				}
+				continue
+			}
+			frag, ok := v.doc.fragments[sel.name]
+			if !ok {
+				v.errorf(sel.loc, "Unknown fragment %q.", sel.name)
+				continue
+			}
+			if v.visiting[sel.name] {
+				v.errorf(sel.loc, "Cannot spread fragment %q within itself.", sel.name)
+				continue
+			}
+			if frag.typeCondition != obj.Name {
+				v.errorf(sel.loc, "Fragment %q cannot be spread here, it is on %q and the parent is %q.", sel.name, frag.typeCondition, obj.Name)
+				continue
+			}
+			key := fragmentKey{name: sel.name, depth: depth}
+			fragComplexity, cached := v.fragments[key]
+			if !cached {
+				v.visiting[sel.name] = true
+				fragComplexity = v.selectionSet(obj, frag.selectionSet, depth)
+				delete(v.visiting, sel.name)
+				v.fragments[key] = fragComplexity
+			}
+			complexity = addCapped(complexity, fragComplexity)
+		case *inlineFragment:
+			if ok, err := included(sel.directives, v.vars); err != nil || !ok {
+				if err != nil {
+					v.errorf(sel.loc, "%s", err)
+				}
+				continue
+			}
+			if sel.typeCondition != "" && sel.typeCondition != obj.Name {
+				v.errorf(sel.loc, "Fragment cannot be spread here, it is on %q and the parent is %q.", sel.typeCondition, obj.Name)
+				continue
+			}
+			complexity = addCapped(complexity, v.selectionSet(obj, sel.selectionSet, depth))
+		}
+		if v.limits.MaxComplexity > 0 && complexity > v.limits.MaxComplexity {
+			v.tooComplex = true
+		}
+	}
+	return complexity
+}
+
+// addCapped y mulCapped saturan en math.MaxInt en vez de desbordar, los operandos no son negativos.
+func addCapped(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+func mulCapped(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+func (v *validator) field(obj *Object, f *field, depth int) int {
+	if ok, err := included(f.directives, v.vars); err != nil || !ok {
+		if err != nil {
+			v.errorf(f.loc, "%s", err)
+		}
+		return 0
+	}
+	if f.name == "__typename" {
+		if f.selectionSet != nil {
+			v.errorf(f.loc, "Field \"__typename\" must not have a selection.")
+		}
+		return 1
+	}
+	def := obj.field(f.name)
+	if def == nil {
+		v.errorf(f.loc, "Cannot query field %q on type %q.", f.name, obj.Name)
+		return 0
+	}
+	args, err := coerceArgs(def, f, v.vars)
+	if err != nil {
+		v.errorf(f.loc, "%s", err)
+		return 0
+	}
+	child, isObject := namedType(def.Type).(*Object)
+	switch {
+	case isObject && f.selectionSet == nil:
+		v.errorf(f.loc, "Field %q of type %q must have a selection of subfields.", f.name, def.Type)
+		return 0
+	case !isObject && f.selectionSet != nil:
+		v.errorf(f.loc, "Field %q must not have a selection since type %q has no subfields.", f.name, def.Type)
+		return 0
+	case !isObject:
+		return 1
+	}
+	multiplier := 1
+	if def.Multiplier != nil {
+		multiplier = def.Multiplier(args)
+	}
+	// un multiplicador negativo restaria complejidad, un alias podria compensar otro campo caro
+	if multiplier < 0 {
+		v.errorf(f.loc, "Field %q cannot return a negative number of elements.", f.name)
+		return 0
+	}
+	return addCapped(1, mulCapped(multiplier, v.selectionSet(child, f.selectionSet, depth+1)))
+}
+
+type executor struct {
+	ctx  context.Context
+	doc  *document
+	vars map[string]any
+	errs []*Error
+}
+
+func (e *executor) addError(err error, f *field, path []any) {
+	gqlErr := &Error{Message: err.Error()}
+	var resolverErr *Error
+	if errors.As(err, &resolverErr) {
+		gqlErr.Message = resolverErr.Message
+		gqlErr.Extensions = resolverErr.Extensions
+	}
+	gqlErr.Locations = []Location{f.loc}
+	gqlErr.Path = append([]any(nil), path...)
+	e.errs = append(e.errs, gqlErr)
+}
+
+// collectFields agrupa los campos de las selecciones por su clave en la respuesta, expandiendo los
+// fragments. La validacion ya verifico que son campos de obj.
+func (e *executor) collectFields(obj *Object, set []selection, keys *[]string, fields map[string][]*field) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *field:
+			if ok, _ := included(sel.directives, e.vars); !ok {
+				continue
+			}
+			key := sel.responseKey()
+			if _, ok := fields[key]; !ok {
+				*keys = append(*keys, key)
+			}
+			fields[key] = append(fields[key], sel)
+		case *fragmentSpread:
+			if ok, _ := included(sel.directives, e.vars); ok {
+				e.collectFields(obj, e.doc.fragments[sel.name].selectionSet, keys, fields)
+			}
+		case *inlineFragment:
+			if ok, _ := included(sel.directives, e.vars); ok {
+				e.collectFields(obj, sel.selectionSet, keys, fields)
+			}
+		}
+	}
+}
+
+// selectionSet ejecuta los campos en orden, retorna false si un campo non-null resulto null y el
+// objeto entero tiene que ser null.
+func (e *executor) selectionSet(obj *Object, source any, set []selection, path []any) (*orderedMap, bool) {
+	var keys []string
+	fields := map[string][]*field{}
+	e.collectFields(obj, set, &keys, fields)
+
+	result := &orderedMap{}
+	for _, key := range keys {
+		f := fields[key][0]
+		if f.name == "__typename" {
+			result.set(key, obj.Name)
+			continue
+		}
+		def := obj.field(f.name)
+		fieldPath := append(path[:len(path):len(path)], key)
+
+		value, ok := e.resolve(def, f, source, fieldPath)
+		if ok {
+			var sub []selection
+			for _, same := range fields[key] {
+				sub = append(sub, same.selectionSet...)
+			}
+			value, ok = e.complete(def.Type, f, sub, value, fieldPath)
+		}
+		if !ok {
+			if _, nonNull := def.Type.(*NonNull); nonNull {
+				return nil, false
+			}
+			value = nil
+		}
+		result.set(key, value)
+	}
+	return result, true
+}
+
+func (e *executor) resolve(def *Field, f *field, source any, path []any) (any, bool) {
+	if err := e.ctx.Err(); err != nil {
+		e.addError(err, f, path)
+		return nil, false
+	}
+	args, err := coerceArgs(def, f, e.vars)
+	if err != nil {
+		e.addError(err, f, path)
+		return nil, false
+	}
+	if def.Resolve == nil {
+		m, _ := source.(map[string]any)
+		return m[def.Name], true
+	}
+	value, err := def.Resolve(e.ctx, source, args)
+	if err != nil {
+		e.addError(err, f, path)
+		return nil, false
+	}
+	return value, true
+}
+
+// complete convierte el valor del resolver segun el tipo del campo, retorna false si el valor es
+// null por un error ya informado.
+func (e *executor) complete(t Type, f *field, set []selection, value any, path []any) (any, bool) {
+	if nonNull, ok := t.(*NonNull); ok {
+		completed, ok := e.complete(nonNull.Of, f, set, value, path)
+		if completed == nil {
+			if ok {
+				e.addError(fmt.Errorf("Cannot return null for non-nullable field %q.", f.name), f, path)
+			}
+			return nil, false
+		}
+		return completed, true
+	}
+	if isNil(value) {
+		return nil, true
+	}
+	switch t := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.addError(fmt.Errorf("Expected a list for field %q.", f.name), f, path)
+			return nil, false
+		}
+		items := make([]any, rv.Len())
+		for i := range items {
+			item, ok := e.complete(t.Of, f, set, rv.Index(i).Interface(), append(path[:len(path):len(path)], i))
+			if !ok {
+				if _, nonNull := t.Of.(*NonNull); nonNull {
+					return nil, false
+				}
+			}
+			items[i] = item
+		}
+		return items, true
+	case *Scalar:
+		serialized, ok := t.serialize(value)
+		if !ok {
+			e.addError(fmt.Errorf("%s cannot represent %v.", t.Name, value), f, path)
+			return nil, false
+		}
+		return serialized, true
+	case *Object:
+		obj, ok := e.selectionSet(t, value, set, path)
+		if !ok {
+			return nil, false
+		}
+		return obj, true
+	}
+	return nil, false
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// orderedMap es un objeto de la respuesta, los campos se serializan en el orden de la query.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *orderedMap) set(key string, value any) {
+	if m.values == nil {
+		m.values = map[string]any{}
+	}
+	m.keys = append(m.keys, key)
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
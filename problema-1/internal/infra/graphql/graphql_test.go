@@ -0,0 +1,313 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type node struct {
+	n int
+}
+
+// testSchema tiene numeros enteros con su siguiente y sus multiplos, para probar anidamiento y
+// listas.
+func testSchema() *Schema {
+	number := &Object{Name: "Number", Description: "Un numero"}
+	number.Fields = []*Field{
+		{
+			Name: "value",
+			Type: &NonNull{Of: Int},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(node).n, nil
+			},
+		},
+		{
+			Name: "next",
+			Type: number,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return node{source.(node).n + 1}, nil
+			},
+		},
+		{
+			Name: "multiples",
+			Args: []*Arg{{Name: "count", Type: &NonNull{Of: Int}, Default: 3}},
+			Type: &NonNull{Of: &List{Of: &NonNull{Of: number}}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				var list []node
+				for i := 1; i <= args["count"].(int); i++ {
+					list = append(list, node{source.(node).n * i})
+				}
+				return list, nil
+			},
+			Multiplier: func(args map[string]any) int { return args["count"].(int) },
+		},
+		{
+			Name: "strict",
+			Type: &NonNull{Of: Int},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return nil, &Error{Message: "strict failed", Extensions: map[string]any{"code": "strict"}}
+			},
+		},
+	}
+	return &Schema{Query: &Object{Name: "Query", Fields: []*Field{
+		{
+			Name: "number",
+			Args: []*Arg{{Name: "n", Type: &NonNull{Of: Int}}},
+			Type: number,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				if args["n"].(int) < 0 {
+					return nil, errors.New("negative")
+				}
+				return node{args["n"].(int)}, nil
+			},
+		},
+		{
+			Name: "echo",
+			Args: []*Arg{{Name: "text", Type: String}},
+			Type: String,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				text, _ := args["text"].(string)
+				return text, nil
+			},
+		},
+	}}}
+}
+
+func TestExecute(t *testing.T) {
+	schema := testSchema()
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expected  string
+	}{
+		{
+			"nested fields keep query order",
+			`{ number(n: 2) { next { value } value } }`,
+			nil,
+			`{"data":{"number":{"next":{"value":3},"value":2}}}`,
+		},
+		{
+			"aliases and typename",
+			`{ a: number(n: 1) { value } b: number(n: 5) { v: value __typename } }`,
+			nil,
+			`{"data":{"a":{"value":1},"b":{"v":5,"__typename":"Number"}}}`,
+		},
+		{
+			"variables and defaults",
+			`query Q($n: Int!, $count: Int = 2) { number(n: $n) { multiples(count: $count) { value } } }`,
+			map[string]any{"n": float64(3)},
+			`{"data":{"number":{"multiples":[{"value":3},{"value":6}]}}}`,
+		},
+		{
+			"fragments and directives",
+			`query($skip: Boolean!) { number(n: 4) { ...F ... on Number { next @skip(if: $skip) { value } } } }
+			fragment F on Number { value @include(if: true) }`,
+			map[string]any{"skip": true},
+			`{"data":{"number":{"value":4}}}`,
+		},
+		{
+			"string escapes",
+			`{ echo(text: "a\"bá") }`,
+			nil,
+			`{"data":{"echo":"a\"bá"}}`,
+		},
+		{
+			"resolver error nulls the field",
+			`{ number(n: -1) { value } echo(text: "x") }`,
+			nil,
+			`{"errors":[{"message":"negative","locations":[{"line":1,"column":3}],"path":["number"]}],"data":{"number":null,"echo":"x"}}`,
+		},
+		{
+			"non-null error propagates to the parent",
+			`{ number(n: 1) { strict } }`,
+			nil,
+			`{"errors":[{"message":"strict failed","locations":[{"line":1,"column":18}],"path":["number","strict"],"extensions":{"code":"strict"}}],"data":{"number":null}}`,
+		},
+		{
+			"unknown field",
+			`{ number(n: 1) { nope } }`,
+			nil,
+			`{"errors":[{"message":"Cannot query field \"nope\" on type \"Number\".","locations":[{"line":1,"column":18}]}]}`,
+		},
+		{
+			"missing argument",
+			`{ number { value } }`,
+			nil,
+			`{"errors":[{"message":"Field \"number\" argument \"n\" of type \"Int!\" is required.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			"invalid variable",
+			`query($n: Int!) { number(n: $n) { value } }`,
+			map[string]any{"n": "1"},
+			`{"errors":[{"message":"Variable \"$n\" got invalid value: Int cannot represent \"1\".","locations":[{"line":1,"column":7}]}]}`,
+		},
+		{
+			"missing selection",
+			`{ number(n: 1) }`,
+			nil,
+			`{"errors":[{"message":"Field \"number\" of type \"Number\" must have a selection of subfields.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			"fragment cycle",
+			`{ number(n: 1) { ...A } } fragment A on Number { next { ...A } }`,
+			nil,
+			`{"errors":[{"message":"Cannot spread fragment \"A\" within itself.","locations":[{"line":1,"column":57}]}]}`,
+		},
+		{
+			"syntax error",
+			`{ number(n: 1) { value }`,
+			nil,
+			`{"errors":[{"message":"Syntax Error: unexpected end of query","locations":[{"line":1,"column":25}]}]}`,
+		},
+		{
+			"mutations are not supported",
+			`mutation { number(n: 1) { value } }`,
+			nil,
+			`{"errors":[{"message":"Only queries are supported, got mutation.","locations":[{"line":1,"column":1}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), Request{Query: tt.query, Variables: tt.variables}, Limits{})
+			got, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	schema := testSchema()
+
+	tests := []struct {
+		name        string
+		query       string
+		limits      Limits
+		expectedErr string
+	}{
+		{"within depth", `{ number(n: 1) { next { value } } }`, Limits{MaxDepth: 3}, ""},
+		{"too deep", `{ number(n: 1) { next { next { value } } } }`, Limits{MaxDepth: 3}, "Query depth exceeds the limit of 3."},
+		{"deep through fragments", `{ number(n: 1) { ...F } } fragment F on Number { next { next { value } } }`, Limits{MaxDepth: 3}, "Query depth exceeds the limit of 3."},
+		// 1 (number) + 1 (multiples) + 10 * 1 (value)
+		{"within complexity", `{ number(n: 1) { multiples(count: 10) { value } } }`, Limits{MaxComplexity: 12}, ""},
+		{"too complex", `{ number(n: 1) { multiples(count: 11) { value } } }`, Limits{MaxComplexity: 12}, "Query complexity exceeds the limit of 12."},
+		{"negative multiplier", `{ a: number(n: 1) { multiples(count: -100) { value } } b: number(n: 1) { multiples(count: 100) { value } } }`, Limits{MaxComplexity: 200}, "Field \"multiples\" cannot return a negative number of elements."},
+		{"skipped fields are free", `{ number(n: 1) { multiples(count: 100) @skip(if: true) { value } } }`, Limits{MaxComplexity: 2}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), Request{Query: tt.query}, tt.limits)
+			if tt.expectedErr == "" {
+				if len(resp.Errors) > 0 || resp.Data == nil {
+					t.Errorf("Expected no errors, got %v", resp.Errors)
+				}
+				return
+			}
+			if len(resp.Errors) != 1 || resp.Errors[0].Message != tt.expectedErr {
+				t.Fatalf("Expected error %q, got %v", tt.expectedErr, resp.Errors)
+			}
+			if resp.Data != nil {
+				t.Errorf("Expected no data for a rejected query, got %v", resp.Data)
+			}
+		})
+	}
+}
+
+// fragmentChain arma una query de n fragments donde cada uno expande dos veces al siguiente, su
+// complejidad es 2^n.
+func fragmentChain(n int) string {
+	var query strings.Builder
+	query.WriteString("{ ...F0 }\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&query, "fragment F%d on Query { ...F%d ...F%d }\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&query, "fragment F%d on Query { echo(text: \"a\") }\n", n)
+	return query.String()
+}
+
+func TestFragmentChain(t *testing.T) {
+	schema := testSchema()
+
+	tests := []struct {
+		name   string
+		limits Limits
+	}{
+		{"exceeds the limit", Limits{MaxComplexity: 1000}},
+		// 2^70 no entra en un int, saturar evita que desborde y pase el limite
+		{"does not overflow", Limits{MaxComplexity: math.MaxInt - 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			resp := schema.Execute(context.Background(), Request{Query: fragmentChain(70)}, tt.limits)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected the validation to fail fast, took %s", elapsed)
+			}
+			expected := fmt.Sprintf("Query complexity exceeds the limit of %d.", tt.limits.MaxComplexity)
+			if len(resp.Errors) != 1 || resp.Errors[0].Message != expected || resp.Data != nil {
+				t.Errorf("Expected error %q, got %v", expected, resp.Errors)
+			}
+		})
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	schema := testSchema()
+	query := `query A { echo(text: "a") } query B { echo(text: "b") }`
+
+	resp := schema.Execute(context.Background(), Request{Query: query, OperationName: "B"}, Limits{})
+	got, _ := json.Marshal(resp)
+	if string(got) != `{"data":{"echo":"b"}}` {
+		t.Errorf("Expected operation B, got %s", got)
+	}
+	resp = schema.Execute(context.Background(), Request{Query: query}, Limits{})
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "operation name") {
+		t.Errorf("Expected operation name error, got %v", resp.Errors)
+	}
+}
+
+func TestSDL(t *testing.T) {
+	expected := `type Query {
+  number(n: Int!): Number
+  echo(text: String): String
+}
+
+"Un numero"
+type Number {
+  value: Int!
+  next: Number
+  multiples(count: Int! = 3): [Number!]!
+  strict: Int!
+}
+`
+	if got := testSchema().SDL(); got != expected {
+		t.Errorf("Expected SDL:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	m := &orderedMap{}
+	m.set("z", 1)
+	m.set("a", []any{"x", nil})
+	got, _ := json.Marshal(m)
+	var decoded map[string]any
+	json.Unmarshal(got, &decoded)
+	if string(got) != `{"z":1,"a":["x",null]}` || !reflect.DeepEqual(decoded["a"], []any{"x", nil}) {
+		t.Errorf("Unexpected encoding %s", got)
+	}
+}
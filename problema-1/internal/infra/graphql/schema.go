@@ -0,0 +1,271 @@
+// Package graphql ejecuta queries GraphQL sobre un schema definido en Go. Implementa el subconjunto
+// que necesita la api: queries con variables, aliases, fragments, @skip e @include, sin mutations,
+// subscriptions ni introspection (salvo __typename), y limita la profundidad y la complejidad de las
+// queries antes de ejecutarlas.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Type es un tipo del schema: *Scalar, *Object, *List o *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar es un tipo escalar, parse convierte los valores de entrada (literales o variables JSON) y
+// serialize los valores que retornan los resolvers.
+type Scalar struct {
+	Name        string
+	Description string
+	parse       func(v any) (any, bool)
+	serialize   func(v any) (any, bool)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Escalares de la especificacion, Int es de 32 bits.
+var (
+	Int = &Scalar{
+		Name:      "Int",
+		parse:     parseInt,
+		serialize: serializeInt,
+	}
+	Float = &Scalar{
+		Name: "Float",
+		parse: func(v any) (any, bool) {
+			switch v := v.(type) {
+			case float64:
+				return v, true
+			case int64:
+				return float64(v), true
+			case int:
+				return float64(v), true
+			}
+			return nil, false
+		},
+		serialize: func(v any) (any, bool) {
+			f, ok := v.(float64)
+			return f, ok
+		},
+	}
+	String = &Scalar{
+		Name: "String",
+		parse: func(v any) (any, bool) {
+			s, ok := v.(string)
+			return s, ok
+		},
+		serialize: func(v any) (any, bool) {
+			s, ok := v.(string)
+			return s, ok
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		parse: func(v any) (any, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+		serialize: func(v any) (any, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+	}
+)
+
+// parseInt acepta los Int literales (int64), los numeros enteros de las variables JSON (float64) y
+// las variables ya convertidas (int).
+func parseInt(v any) (any, bool) {
+	var i int64
+	switch v := v.(type) {
+	case int:
+		i = int64(v)
+	case int64:
+		i = v
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+			return nil, false
+		}
+		i = int64(v)
+	default:
+		return nil, false
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return nil, false
+	}
+	return int(i), true
+}
+
+func serializeInt(v any) (any, bool) {
+	var i int64
+	switch v := v.(type) {
+	case int:
+		i = int64(v)
+	case int32:
+		i = int64(v)
+	case int64:
+		i = v
+	case uint:
+		if v > math.MaxInt32 {
+			return nil, false
+		}
+		i = int64(v)
+	case uint32:
+		i = int64(v)
+	case uint64:
+		if v > math.MaxInt32 {
+			return nil, false
+		}
+		i = int64(v)
+	default:
+		return nil, false
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return nil, false
+	}
+	return int(i), true
+}
+
+// Object es un tipo objeto. Los Fields se pueden asignar despues de crearlo para los tipos que se
+// referencian a si mismos.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+func (o *Object) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+type List struct {
+	Of Type
+}
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+type NonNull struct {
+	Of Type
+}
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+// Resolver retorna el valor del campo a partir del valor del objeto padre y los argumentos ya
+// validados, los argumentos opcionales sin valor no estan en args.
+type Resolver func(ctx context.Context, source any, args map[string]any) (any, error)
+
+type Field struct {
+	Name        string
+	Description string
+	Args        []*Arg
+	Type        Type
+	Resolve     Resolver
+	// Multiplier estima cuantos elementos retorna un campo de lista segun sus argumentos, la
+	// complejidad de sus subcampos se multiplica por este valor. nil cuenta como 1 y un valor
+	// negativo es un error de validacion, el Multiplier deberia acotar los argumentos como el
+	// Resolve.
+	Multiplier func(args map[string]any) int
+}
+
+type Arg struct {
+	Name        string
+	Description string
+	Type        Type
+	// Default es el valor si no se pasa el argumento, nil si no tiene
+	Default any
+}
+
+type Schema struct {
+	Query *Object
+}
+
+// SDL retorna el schema en el lenguaje de definicion de GraphQL.
+func (s *Schema) SDL() string {
+	var b strings.Builder
+	seen := map[Type]bool{}
+	queue := []Type{s.Query}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		obj, ok := t.(*Object)
+		if !ok {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		writeDescription(&b, "", obj.Description)
+		fmt.Fprintf(&b, "type %s {\n", obj.Name)
+		for _, f := range obj.Fields {
+			writeDescription(&b, "  ", f.Description)
+			fmt.Fprintf(&b, "  %s", f.Name)
+			if len(f.Args) > 0 {
+				var args []string
+				for _, a := range f.Args {
+					arg := a.Name + ": " + a.Type.String()
+					if a.Default != nil {
+						arg += fmt.Sprintf(" = %v", a.Default)
+					}
+					args = append(args, arg)
+				}
+				fmt.Fprintf(&b, "(%s)", strings.Join(args, ", "))
+			}
+			fmt.Fprintf(&b, ": %s\n", f.Type)
+			queue = append(queue, namedType(f.Type))
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent string, description string) {
+	if description != "" {
+		fmt.Fprintf(b, "%s%q\n", indent, description)
+	}
+}
+
+// namedType retorna el tipo sin las listas ni los non-null.
+func namedType(t Type) Type {
+	for {
+		switch w := t.(type) {
+		case *List:
+			t = w.Of
+		case *NonNull:
+			t = w.Of
+		default:
+			return t
+		}
+	}
+}
+
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error es un error de la respuesta. Los resolvers pueden retornar un *Error para elegir el mensaje
+// y las extensions, cualquier otro error se responde con su texto.
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
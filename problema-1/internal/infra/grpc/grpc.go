@@ -0,0 +1,403 @@
+// Package grpc_adapter expone las conversiones de app por gRPC, con health checking y reflection,
+// junto a la api http y compartiendo la misma instancia de app.
+package grpc_adapter
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative -I patentespb patentespb/patentes.proto
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/grpc/patentespb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// errorDomain es el dominio de los errdetails.ErrorInfo con el codigo de los errores de app.
+	errorDomain = "patentes"
+	// maxBatch acota los items de un stream de ConvertBatch, cada item ademas se cobra en Limit.
+	maxBatch = 1000
+)
+
+// ErrPermissionDenied lo retorna Authorize cuando las credenciales son validas pero no alcanzan,
+// cualquier otro error se responde como Unauthenticated.
+var ErrPermissionDenied = errors.New("grpc: permission denied")
+
+// LimitError lo retorna Limit cuando el cliente agoto sus requests, se responde ResourceExhausted con
+// el codigo en un ErrorInfo y la espera en un RetryInfo.
+type LimitError struct {
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return "grpc: " + e.Message
+}
+
+func (e *LimitError) status() error {
+	st, err := status.New(codes.ResourceExhausted, e.Message).WithDetails(
+		&errdetails.ErrorInfo{Reason: e.Code, Domain: errorDomain},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(e.RetryAfter)},
+	)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, e.Message)
+	}
+	return st.Err()
+}
+
+type Options struct {
+	Logger *slog.Logger
+	// TLS es nil para servir sin TLS, con ClientCAs se exige certificado de cliente.
+	TLS *tls.Config
+	// Authorize valida las credenciales de las rpc de Converter, method es el nombre completo de la
+	// rpc, ip la del cliente, token viene de la metadata authorization (Bearer) o x-api-key y cert es
+	// el certificado de cliente verificado, ambos pueden venir vacios. Retorna la clave del cliente
+	// para Limit, un *LimitError se responde igual que en Limit. nil si no se exige autenticacion.
+	Authorize func(method string, ip string, token string, cert *x509.Certificate) (string, error)
+	// Limit cobra una conversion al cliente, se llama en cada rpc unaria y en cada item de
+	// ConvertBatch. Sin Authorize la clave es "ip:" y la IP del cliente, como en la api http. nil si
+	// no hay limites.
+	Limit func(key string) error
+}
+
+// GRPC es el servidor gRPC, el equivalente de http_adapter.HTTP.
+type GRPC struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+func New(service app.ServiceV1, opts Options) *GRPC {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	i := interceptors{logger: logger, authorize: opts.Authorize}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	}
+	if opts.TLS != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(opts.TLS)))
+	}
+
+	g := &GRPC{
+		server: grpc.NewServer(serverOpts...),
+		health: health.NewServer(),
+	}
+	patentespb.RegisterConverterServer(g.server, &converter{app: service, logger: logger, limit: opts.Limit})
+	healthpb.RegisterHealthServer(g.server, g.health)
+	g.health.SetServingStatus(patentespb.Converter_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	reflection.Register(g.server)
+	return g
+}
+
+// Serve atiende las conexiones de lis hasta que se llame a Shutdown o Close.
+func (g *GRPC) Serve(lis net.Listener) error {
+	return g.server.Serve(lis)
+}
+
+// Shutdown reporta NOT_SERVING en el health check y espera que terminen las rpc en curso, si ctx
+// vence antes las corta.
+func (g *GRPC) Shutdown(ctx context.Context) error {
+	g.health.Shutdown()
+	done := make(chan struct{})
+	go func() {
+		g.server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.server.Stop()
+		return ctx.Err()
+	}
+}
+
+// Close corta todas las conexiones de inmediato.
+func (g *GRPC) Close() {
+	g.server.Stop()
+}
+
+type converter struct {
+	patentespb.UnimplementedConverterServer
+	app    app.ServiceV1
+	logger *slog.Logger
+	limit  func(key string) error
+}
+
+func (c *converter) PatentFromID(ctx context.Context, req *patentespb.PatentFromIDRequest) (*patentespb.PatentFromIDResponse, error) {
+	if err := c.charge(ctx); err != nil {
+		return nil, err
+	}
+	patente, err := c.app.PatentFromID(ctx, uint(req.GetId()))
+	if err != nil {
+		return nil, c.status(ctx, err)
+	}
+	return &patentespb.PatentFromIDResponse{Patente: patente}, nil
+}
+
+func (c *converter) IDFromPatent(ctx context.Context, req *patentespb.IDFromPatentRequest) (*patentespb.IDFromPatentResponse, error) {
+	if err := c.charge(ctx); err != nil {
+		return nil, err
+	}
+	id, err := c.app.IDFromPatent(ctx, req.GetPatente())
+	if err != nil {
+		return nil, c.status(ctx, err)
+	}
+	return &patentespb.IDFromPatentResponse{Id: uint64(id)}, nil
+}
+
+func (c *converter) ConvertBatch(stream grpc.BidiStreamingServer[patentespb.ConvertRequest, patentespb.ConvertResponse]) error {
+	ctx := stream.Context()
+	for items := 1; ; items++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if items > maxBatch {
+			return status.Errorf(codes.ResourceExhausted, "batch exceeds %d items", maxBatch)
+		}
+		if err := c.charge(ctx); err != nil {
+			return err
+		}
+
+		resp := &patentespb.ConvertResponse{Ref: req.GetRef()}
+		switch input := req.GetInput().(type) {
+		case *patentespb.ConvertRequest_Id:
+			patente, err := c.app.PatentFromID(ctx, uint(input.Id))
+			if err != nil {
+				resp.Result, err = c.batchError(ctx, err)
+				if err != nil {
+					return err
+				}
+			} else {
+				resp.Result = &patentespb.ConvertResponse_Patente{Patente: patente}
+			}
+		case *patentespb.ConvertRequest_Patente:
+			id, err := c.app.IDFromPatent(ctx, input.Patente)
+			if err != nil {
+				resp.Result, err = c.batchError(ctx, err)
+				if err != nil {
+					return err
+				}
+			} else {
+				resp.Result = &patentespb.ConvertResponse_Id{Id: uint64(id)}
+			}
+		default:
+			resp.Result = &patentespb.ConvertResponse_Error{Error: &patentespb.Error{
+				Code:    app.CodeEmpty,
+				Message: "request has no id or patente",
+			}}
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// charge cobra una conversion al cliente de ctx.
+func (c *converter) charge(ctx context.Context) error {
+	if c.limit == nil {
+		return nil
+	}
+	err := c.limit(clientKeyFrom(ctx))
+	if err == nil {
+		return nil
+	}
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		c.logger.ErrorContext(ctx, "Unexpected limit error", "error", err.Error())
+		return status.Error(codes.Internal, "internal error")
+	}
+	return limitErr.status()
+}
+
+// batchError convierte un error de dominio en el resultado del item, los demas errores cortan el
+// stream.
+func (c *converter) batchError(ctx context.Context, err error) (*patentespb.ConvertResponse_Error, error) {
+	code := app.ErrorCode(err)
+	if code == "" {
+		return nil, c.status(ctx, err)
+	}
+	return &patentespb.ConvertResponse_Error{Error: &patentespb.Error{Code: code, Message: err.Error()}}, nil
+}
+
+// status convierte un error de app en un status gRPC. Los errores de dominio son InvalidArgument con
+// el codigo en un ErrorInfo, un error sin codigo es interno y su texto no se expone al cliente.
+func (c *converter) status(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	code := app.ErrorCode(err)
+	if code == "" {
+		c.logger.ErrorContext(ctx, "Unexpected app error", "error", err.Error())
+		return status.Error(codes.Internal, "internal error")
+	}
+	st, detailErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason: code,
+		Domain: errorDomain,
+	})
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+// ErrorCode retorna el codigo de dominio de un error de las rpc, vacio si no tiene.
+func ErrorCode(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == errorDomain {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+// interceptors loguean cada rpc y aplican Authorize a las rpc de Converter, health y reflection
+// quedan abiertas para los balanceadores y herramientas.
+type interceptors struct {
+	logger    *slog.Logger
+	authorize func(method string, ip string, token string, cert *x509.Certificate) (string, error)
+}
+
+type clientKeyContextKey struct{}
+
+// clientKeyFrom retorna la clave del cliente que dejo check en el contexto.
+func clientKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(clientKeyContextKey{}).(string)
+	return key
+}
+
+// keyedStream reemplaza el contexto del stream por el que tiene la clave del cliente.
+type keyedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *keyedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (i interceptors) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	var resp any
+	ctx, err := i.check(ctx, info.FullMethod)
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	i.log(ctx, info.FullMethod, err, start)
+	return resp, err
+}
+
+func (i interceptors) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	ctx, err := i.check(ss.Context(), info.FullMethod)
+	if err == nil {
+		err = handler(srv, &keyedStream{ServerStream: ss, ctx: ctx})
+	}
+	i.log(ctx, info.FullMethod, err, start)
+	return err
+}
+
+// check autoriza las rpc de Converter y deja en el contexto la clave del cliente para los limites.
+func (i interceptors) check(ctx context.Context, method string) (context.Context, error) {
+	if !strings.HasPrefix(method, "/"+patentespb.Converter_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+	if i.authorize == nil {
+		return context.WithValue(ctx, clientKeyContextKey{}, "ip:"+peerIP(ctx)), nil
+	}
+	key, err := i.authorize(method, peerIP(ctx), token(ctx), clientCert(ctx))
+	var limitErr *LimitError
+	switch {
+	case err == nil:
+		return context.WithValue(ctx, clientKeyContextKey{}, key), nil
+	case errors.As(err, &limitErr):
+		return ctx, limitErr.status()
+	case errors.Is(err, ErrPermissionDenied):
+		return ctx, status.Error(codes.PermissionDenied, err.Error())
+	}
+	return ctx, status.Error(codes.Unauthenticated, err.Error())
+}
+
+func (i interceptors) log(ctx context.Context, method string, err error, start time.Time) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	if code == codes.Internal || code == codes.Unknown {
+		level = slog.LevelError
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("remote_addr", p.Addr.String()))
+	}
+	i.logger.LogAttrs(ctx, level, "RPC served", attrs...)
+}
+
+// token retorna la credencial de la metadata, x-api-key o authorization: Bearer.
+func token(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		return values[0]
+	}
+	for _, value := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(value, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// peerIP retorna la IP del cliente sin el puerto.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// clientCert retorna el certificado de cliente verificado por TLS, nil si no hay.
+func clientCert(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
@@ -0,0 +1,313 @@
+package grpc_adapter
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/grpc/patentespb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func setupTestServer(t *testing.T, opts Options) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	g := New(&app.App{}, opts)
+	go g.Serve(lis)
+	t.Cleanup(g.Close)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestUnary(t *testing.T) {
+	client := patentespb.NewConverterClient(setupTestServer(t, Options{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	patent, err := client.PatentFromID(ctx, &patentespb.PatentFromIDRequest{Id: 1})
+	if err != nil || patent.GetPatente() != "AAAA000" {
+		t.Errorf("Expected AAAA000, got %v %v", patent, err)
+	}
+	id, err := client.IDFromPatent(ctx, &patentespb.IDFromPatentRequest{Patente: "AAAA001"})
+	if err != nil || id.GetId() != 2 {
+		t.Errorf("Expected 2, got %v %v", id, err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		code string
+	}{
+		{"id out of range", func() error {
+			_, err := client.PatentFromID(ctx, &patentespb.PatentFromIDRequest{Id: app.MaxID + 1})
+			return err
+		}, "invalid_range"},
+		{"empty patent", func() error {
+			_, err := client.IDFromPatent(ctx, &patentespb.IDFromPatentRequest{})
+			return err
+		}, app.CodeEmpty},
+		{"bad format", func() error {
+			_, err := client.IDFromPatent(ctx, &patentespb.IDFromPatentRequest{Patente: "A1"})
+			return err
+		}, "bad_format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("Expected InvalidArgument, got %v", err)
+			}
+			if got := ErrorCode(err); got != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, got)
+			}
+		})
+	}
+}
+
+func TestConvertBatch(t *testing.T) {
+	client := patentespb.NewConverterClient(setupTestServer(t, Options{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.ConvertBatch(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	requests := []*patentespb.ConvertRequest{
+		{Ref: "a", Input: &patentespb.ConvertRequest_Id{Id: 1}},
+		{Ref: "b", Input: &patentespb.ConvertRequest_Patente{Patente: "AAAA001"}},
+		{Ref: "c", Input: &patentespb.ConvertRequest_Id{Id: 0}},
+		{Ref: "d"},
+	}
+	for _, req := range requests {
+		if err := stream.Send(req); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	stream.CloseSend()
+
+	var responses []*patentespb.ConvertResponse
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		responses = append(responses, resp)
+	}
+
+	if len(responses) != 4 {
+		t.Fatalf("Expected 4 responses, got %d", len(responses))
+	}
+	if responses[0].GetRef() != "a" || responses[0].GetPatente() != "AAAA000" {
+		t.Errorf("Expected a=AAAA000, got %v", responses[0])
+	}
+	if responses[1].GetRef() != "b" || responses[1].GetId() != 2 {
+		t.Errorf("Expected b=2, got %v", responses[1])
+	}
+	if responses[2].GetError().GetCode() != "invalid_range" {
+		t.Errorf("Expected invalid_range for c, got %v", responses[2])
+	}
+	if responses[3].GetError().GetCode() != app.CodeEmpty {
+		t.Errorf("Expected empty for d, got %v", responses[3])
+	}
+}
+
+func TestHealthAndReflection(t *testing.T) {
+	conn := setupTestServer(t, Options{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: patentespb.Converter_ServiceDesc.ServiceName,
+	})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %v %v", resp, err)
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	info, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	found := false
+	for _, service := range info.GetListServicesResponse().GetService() {
+		found = found || service.GetName() == patentespb.Converter_ServiceDesc.ServiceName
+	}
+	if !found {
+		t.Errorf("Expected reflection to list %s", patentespb.Converter_ServiceDesc.ServiceName)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	conn := setupTestServer(t, Options{
+		Authorize: func(method string, ip string, token string, cert *x509.Certificate) (string, error) {
+			switch token {
+			case "good":
+				return "api_key:good", nil
+			case "reader":
+				return "", ErrPermissionDenied
+			case "blocked":
+				return "", &LimitError{Code: "rate_limited", Message: "too many failed authentications", RetryAfter: time.Minute}
+			}
+			return "", errors.New("invalid credentials")
+		},
+	})
+	client := patentespb.NewConverterClient(conn)
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		expected codes.Code
+	}{
+		{"without credentials", metadata.MD{}, codes.Unauthenticated},
+		{"bearer", metadata.Pairs("authorization", "Bearer good"), codes.OK},
+		{"api key header", metadata.Pairs("x-api-key", "good"), codes.OK},
+		{"missing scope", metadata.Pairs("x-api-key", "reader"), codes.PermissionDenied},
+		{"invalid", metadata.Pairs("x-api-key", "bad"), codes.Unauthenticated},
+		{"blocked ip", metadata.Pairs("x-api-key", "blocked"), codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), tt.md), 5*time.Second)
+			defer cancel()
+			_, err := client.PatentFromID(ctx, &patentespb.PatentFromIDRequest{Id: 1})
+			if status.Code(err) != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// el health check no exige credenciales
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("Expected anonymous health check, got %v", err)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	var charged []string
+	conn := setupTestServer(t, Options{
+		Authorize: func(method string, ip string, token string, cert *x509.Certificate) (string, error) {
+			return "api_key:" + token, nil
+		},
+		Limit: func(key string) error {
+			charged = append(charged, key)
+			if len(charged) > 3 {
+				return &LimitError{Code: "rate_limited", Message: "too many requests", RetryAfter: 2 * time.Second}
+			}
+			return nil
+		},
+	})
+	client := patentespb.NewConverterClient(conn)
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-api-key", "k1")), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.PatentFromID(ctx, &patentespb.PatentFromIDRequest{Id: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// cada item del batch se cobra, el cuarto agota el limite y corta el stream
+	stream, err := client.ConvertBatch(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := range 5 {
+		stream.Send(&patentespb.ConvertRequest{Input: &patentespb.ConvertRequest_Id{Id: uint64(i + 1)}})
+	}
+	stream.CloseSend()
+	var received int
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+		received++
+	}
+	if received != 2 || status.Code(err) != codes.ResourceExhausted || ErrorCode(err) != "rate_limited" {
+		t.Fatalf("Expected 2 items and ResourceExhausted, got %d items and %v", received, err)
+	}
+	for _, key := range charged {
+		if key != "api_key:k1" {
+			t.Errorf("Expected the charges on api_key:k1, got %s", key)
+		}
+	}
+
+	_, err = client.IDFromPatent(ctx, &patentespb.IDFromPatentRequest{Patente: "AAAA000"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Expected ResourceExhausted, got %v", err)
+	}
+	var retry *errdetails.RetryInfo
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry.GetRetryDelay().AsDuration() != 2*time.Second {
+		t.Errorf("Expected a retry delay of 2s, got %v", retry)
+	}
+}
+
+func TestConvertBatchMaxItems(t *testing.T) {
+	client := patentespb.NewConverterClient(setupTestServer(t, Options{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.ConvertBatch(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	go func() {
+		for i := range maxBatch + 1 {
+			if stream.Send(&patentespb.ConvertRequest{Input: &patentespb.ConvertRequest_Id{Id: uint64(i + 1)}}) != nil {
+				return
+			}
+		}
+		stream.CloseSend()
+	}()
+	var received int
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+		received++
+	}
+	if received != maxBatch || status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected %d items and ResourceExhausted, got %d and %v", maxBatch, received, err)
+	}
+}
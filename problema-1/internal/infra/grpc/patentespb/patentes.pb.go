@@ -0,0 +1,573 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: patentes.proto
+
+// Conversion entre patentes y sus ids, las mismas operaciones que la api http.
+
+package patentespb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PatentFromIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *PatentFromIDRequest) Reset() {
+	*x = PatentFromIDRequest{}
+	mi := &file_patentes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatentFromIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatentFromIDRequest) ProtoMessage() {}
+
+func (x *PatentFromIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatentFromIDRequest.ProtoReflect.Descriptor instead.
+func (*PatentFromIDRequest) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PatentFromIDRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PatentFromIDResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Patente string `protobuf:"bytes,1,opt,name=patente,proto3" json:"patente,omitempty"`
+}
+
+func (x *PatentFromIDResponse) Reset() {
+	*x = PatentFromIDResponse{}
+	mi := &file_patentes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatentFromIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatentFromIDResponse) ProtoMessage() {}
+
+func (x *PatentFromIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatentFromIDResponse.ProtoReflect.Descriptor instead.
+func (*PatentFromIDResponse) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PatentFromIDResponse) GetPatente() string {
+	if x != nil {
+		return x.Patente
+	}
+	return ""
+}
+
+type IDFromPatentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Patente string `protobuf:"bytes,1,opt,name=patente,proto3" json:"patente,omitempty"`
+}
+
+func (x *IDFromPatentRequest) Reset() {
+	*x = IDFromPatentRequest{}
+	mi := &file_patentes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IDFromPatentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IDFromPatentRequest) ProtoMessage() {}
+
+func (x *IDFromPatentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IDFromPatentRequest.ProtoReflect.Descriptor instead.
+func (*IDFromPatentRequest) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IDFromPatentRequest) GetPatente() string {
+	if x != nil {
+		return x.Patente
+	}
+	return ""
+}
+
+type IDFromPatentResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *IDFromPatentResponse) Reset() {
+	*x = IDFromPatentResponse{}
+	mi := &file_patentes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IDFromPatentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IDFromPatentResponse) ProtoMessage() {}
+
+func (x *IDFromPatentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IDFromPatentResponse.ProtoReflect.Descriptor instead.
+func (*IDFromPatentResponse) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *IDFromPatentResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ConvertRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ref la elige el cliente para asociar la respuesta con el request.
+	Ref string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// Types that are assignable to Input:
+	//	*ConvertRequest_Id
+	//	*ConvertRequest_Patente
+	Input isConvertRequest_Input `protobuf_oneof:"input"`
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_patentes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConvertRequest) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (m *ConvertRequest) GetInput() isConvertRequest_Input {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+func (x *ConvertRequest) GetId() uint64 {
+	if x, ok := x.GetInput().(*ConvertRequest_Id); ok {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConvertRequest) GetPatente() string {
+	if x, ok := x.GetInput().(*ConvertRequest_Patente); ok {
+		return x.Patente
+	}
+	return ""
+}
+
+type isConvertRequest_Input interface {
+	isConvertRequest_Input()
+}
+
+type ConvertRequest_Id struct {
+	Id uint64 `protobuf:"varint,2,opt,name=id,proto3,oneof"`
+}
+
+type ConvertRequest_Patente struct {
+	Patente string `protobuf:"bytes,3,opt,name=patente,proto3,oneof"`
+}
+
+func (*ConvertRequest_Id) isConvertRequest_Input() {}
+
+func (*ConvertRequest_Patente) isConvertRequest_Input() {}
+
+type ConvertResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ref string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// Types that are assignable to Result:
+	//	*ConvertResponse_Patente
+	//	*ConvertResponse_Id
+	//	*ConvertResponse_Error
+	Result isConvertResponse_Result `protobuf_oneof:"result"`
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_patentes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConvertResponse) GetRef() string {
+	if x != nil {
+		return x.Ref
+	}
+	return ""
+}
+
+func (m *ConvertResponse) GetResult() isConvertResponse_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *ConvertResponse) GetPatente() string {
+	if x, ok := x.GetResult().(*ConvertResponse_Patente); ok {
+		return x.Patente
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetId() uint64 {
+	if x, ok := x.GetResult().(*ConvertResponse_Id); ok {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetError() *Error {
+	if x, ok := x.GetResult().(*ConvertResponse_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isConvertResponse_Result interface {
+	isConvertResponse_Result()
+}
+
+type ConvertResponse_Patente struct {
+	Patente string `protobuf:"bytes,2,opt,name=patente,proto3,oneof"`
+}
+
+type ConvertResponse_Id struct {
+	Id uint64 `protobuf:"varint,3,opt,name=id,proto3,oneof"`
+}
+
+type ConvertResponse_Error struct {
+	Error *Error `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*ConvertResponse_Patente) isConvertResponse_Result() {}
+
+func (*ConvertResponse_Id) isConvertResponse_Result() {}
+
+func (*ConvertResponse_Error) isConvertResponse_Result() {}
+
+// Error es un error de conversion con el mismo codigo que la api http, por ejemplo invalid_range.
+type Error struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_patentes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_patentes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_patentes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_patentes_proto protoreflect.FileDescriptor
+
+var file_patentes_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x0b, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x22, 0x25, 0x0a,
+	0x13, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x49, 0x44, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x02, 0x69, 0x64, 0x22, 0x30, 0x0a, 0x14, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x46, 0x72,
+	0x6f, 0x6d, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
+	0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x22, 0x2f, 0x0a, 0x13, 0x49, 0x44, 0x46, 0x72, 0x6f, 0x6d,
+	0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x22, 0x26, 0x0a, 0x14, 0x49, 0x44, 0x46, 0x72, 0x6f,
+	0x6d, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x59, 0x0a, 0x0e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x72, 0x65, 0x66, 0x12, 0x10, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x00, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74,
+	0x65, 0x42, 0x07, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x22, 0x87, 0x01, 0x0a, 0x0f, 0x43,
+	0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66,
+	0x12, 0x1a, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x07, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x48, 0x00, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2a,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x22, 0x35, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x84, 0x02, 0x0a, 0x09,
+	0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x65, 0x72, 0x12, 0x53, 0x0a, 0x0c, 0x50, 0x61, 0x74,
+	0x65, 0x6e, 0x74, 0x46, 0x72, 0x6f, 0x6d, 0x49, 0x44, 0x12, 0x20, 0x2e, 0x70, 0x61, 0x74, 0x65,
+	0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x46, 0x72,
+	0x6f, 0x6d, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x61,
+	0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74,
+	0x46, 0x72, 0x6f, 0x6d, 0x49, 0x44, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53,
+	0x0a, 0x0c, 0x49, 0x44, 0x46, 0x72, 0x6f, 0x6d, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x20,
+	0x2e, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x44, 0x46,
+	0x72, 0x6f, 0x6d, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x21, 0x2e, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49,
+	0x44, 0x46, 0x72, 0x6f, 0x6d, 0x50, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x12, 0x1b, 0x2e, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43,
+	0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01,
+	0x30, 0x01, 0x42, 0x48, 0x5a, 0x46, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x64, 0x6f, 0x2d, 0x70, 0x72, 0x75, 0x65, 0x62, 0x61, 0x2d, 0x74, 0x65, 0x63, 0x6e, 0x69,
+	0x63, 0x61, 0x2f, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x61, 0x2d, 0x31, 0x2f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x69, 0x6e, 0x66, 0x72, 0x61, 0x2f, 0x67, 0x72, 0x70,
+	0x63, 0x2f, 0x70, 0x61, 0x74, 0x65, 0x6e, 0x74, 0x65, 0x73, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_patentes_proto_rawDescOnce sync.Once
+	file_patentes_proto_rawDescData = file_patentes_proto_rawDesc
+)
+
+func file_patentes_proto_rawDescGZIP() []byte {
+	file_patentes_proto_rawDescOnce.Do(func() {
+		file_patentes_proto_rawDescData = protoimpl.X.CompressGZIP(file_patentes_proto_rawDescData)
+	})
+	return file_patentes_proto_rawDescData
+}
+
+var file_patentes_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_patentes_proto_goTypes = []any{
+	(*PatentFromIDRequest)(nil),  // 0: patentes.v1.PatentFromIDRequest
+	(*PatentFromIDResponse)(nil), // 1: patentes.v1.PatentFromIDResponse
+	(*IDFromPatentRequest)(nil),  // 2: patentes.v1.IDFromPatentRequest
+	(*IDFromPatentResponse)(nil), // 3: patentes.v1.IDFromPatentResponse
+	(*ConvertRequest)(nil),       // 4: patentes.v1.ConvertRequest
+	(*ConvertResponse)(nil),      // 5: patentes.v1.ConvertResponse
+	(*Error)(nil),                // 6: patentes.v1.Error
+}
+var file_patentes_proto_depIdxs = []int32{
+	6, // 0: patentes.v1.ConvertResponse.error:type_name -> patentes.v1.Error
+	0, // 1: patentes.v1.Converter.PatentFromID:input_type -> patentes.v1.PatentFromIDRequest
+	2, // 2: patentes.v1.Converter.IDFromPatent:input_type -> patentes.v1.IDFromPatentRequest
+	4, // 3: patentes.v1.Converter.ConvertBatch:input_type -> patentes.v1.ConvertRequest
+	1, // 4: patentes.v1.Converter.PatentFromID:output_type -> patentes.v1.PatentFromIDResponse
+	3, // 5: patentes.v1.Converter.IDFromPatent:output_type -> patentes.v1.IDFromPatentResponse
+	5, // 6: patentes.v1.Converter.ConvertBatch:output_type -> patentes.v1.ConvertResponse
+	4, // [4:7] is the sub-list for method output_type
+	1, // [1:4] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_patentes_proto_init() }
+func file_patentes_proto_init() {
+	if File_patentes_proto != nil {
+		return
+	}
+	file_patentes_proto_msgTypes[4].OneofWrappers = []any{
+		(*ConvertRequest_Id)(nil),
+		(*ConvertRequest_Patente)(nil),
+	}
+	file_patentes_proto_msgTypes[5].OneofWrappers = []any{
+		(*ConvertResponse_Patente)(nil),
+		(*ConvertResponse_Id)(nil),
+		(*ConvertResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_patentes_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_patentes_proto_goTypes,
+		DependencyIndexes: file_patentes_proto_depIdxs,
+		MessageInfos:      file_patentes_proto_msgTypes,
+	}.Build()
+	File_patentes_proto = out.File
+	file_patentes_proto_rawDesc = nil
+	file_patentes_proto_goTypes = nil
+	file_patentes_proto_depIdxs = nil
+}
@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: patentes.proto
+
+// Conversion entre patentes y sus ids, las mismas operaciones que la api http.
+
+package patentespb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Converter_PatentFromID_FullMethodName = "/patentes.v1.Converter/PatentFromID"
+	Converter_IDFromPatent_FullMethodName = "/patentes.v1.Converter/IDFromPatent"
+	Converter_ConvertBatch_FullMethodName = "/patentes.v1.Converter/ConvertBatch"
+)
+
+// ConverterClient is the client API for Converter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ConverterClient interface {
+	// PatentFromID convierte un id en su patente.
+	PatentFromID(ctx context.Context, in *PatentFromIDRequest, opts ...grpc.CallOption) (*PatentFromIDResponse, error)
+	// IDFromPatent convierte una patente en su id.
+	IDFromPatent(ctx context.Context, in *IDFromPatentRequest, opts ...grpc.CallOption) (*IDFromPatentResponse, error)
+	// ConvertBatch convierte un stream de ids o patentes, cada respuesta lleva la referencia de su
+	// request y un error de conversion no corta el stream.
+	ConvertBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ConvertRequest, ConvertResponse], error)
+}
+
+type converterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewConverterClient(cc grpc.ClientConnInterface) ConverterClient {
+	return &converterClient{cc}
+}
+
+func (c *converterClient) PatentFromID(ctx context.Context, in *PatentFromIDRequest, opts ...grpc.CallOption) (*PatentFromIDResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PatentFromIDResponse)
+	err := c.cc.Invoke(ctx, Converter_PatentFromID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *converterClient) IDFromPatent(ctx context.Context, in *IDFromPatentRequest, opts ...grpc.CallOption) (*IDFromPatentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IDFromPatentResponse)
+	err := c.cc.Invoke(ctx, Converter_IDFromPatent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *converterClient) ConvertBatch(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ConvertRequest, ConvertResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Converter_ServiceDesc.Streams[0], Converter_ConvertBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ConvertRequest, ConvertResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Converter_ConvertBatchClient = grpc.BidiStreamingClient[ConvertRequest, ConvertResponse]
+
+// ConverterServer is the server API for Converter service.
+// All implementations must embed UnimplementedConverterServer
+// for forward compatibility.
+type ConverterServer interface {
+	// PatentFromID convierte un id en su patente.
+	PatentFromID(context.Context, *PatentFromIDRequest) (*PatentFromIDResponse, error)
+	// IDFromPatent convierte una patente en su id.
+	IDFromPatent(context.Context, *IDFromPatentRequest) (*IDFromPatentResponse, error)
+	// ConvertBatch convierte un stream de ids o patentes, cada respuesta lleva la referencia de su
+	// request y un error de conversion no corta el stream.
+	ConvertBatch(grpc.BidiStreamingServer[ConvertRequest, ConvertResponse]) error
+	mustEmbedUnimplementedConverterServer()
+}
+
+// UnimplementedConverterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedConverterServer struct{}
+
+func (UnimplementedConverterServer) PatentFromID(context.Context, *PatentFromIDRequest) (*PatentFromIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatentFromID not implemented")
+}
+func (UnimplementedConverterServer) IDFromPatent(context.Context, *IDFromPatentRequest) (*IDFromPatentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IDFromPatent not implemented")
+}
+func (UnimplementedConverterServer) ConvertBatch(grpc.BidiStreamingServer[ConvertRequest, ConvertResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ConvertBatch not implemented")
+}
+func (UnimplementedConverterServer) mustEmbedUnimplementedConverterServer() {}
+func (UnimplementedConverterServer) testEmbeddedByValue()                   {}
+
+// UnsafeConverterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ConverterServer will
+// result in compilation errors.
+type UnsafeConverterServer interface {
+	mustEmbedUnimplementedConverterServer()
+}
+
+func RegisterConverterServer(s grpc.ServiceRegistrar, srv ConverterServer) {
+	// If the following call pancis, it indicates UnimplementedConverterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Converter_ServiceDesc, srv)
+}
+
+func _Converter_PatentFromID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatentFromIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConverterServer).PatentFromID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Converter_PatentFromID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConverterServer).PatentFromID(ctx, req.(*PatentFromIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Converter_IDFromPatent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDFromPatentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConverterServer).IDFromPatent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Converter_IDFromPatent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConverterServer).IDFromPatent(ctx, req.(*IDFromPatentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Converter_ConvertBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ConverterServer).ConvertBatch(&grpc.GenericServerStream[ConvertRequest, ConvertResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Converter_ConvertBatchServer = grpc.BidiStreamingServer[ConvertRequest, ConvertResponse]
+
+// Converter_ServiceDesc is the grpc.ServiceDesc for Converter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Converter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "patentes.v1.Converter",
+	HandlerType: (*ConverterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PatentFromID",
+			Handler:    _Converter_PatentFromID_Handler,
+		},
+		{
+			MethodName: "IDFromPatent",
+			Handler:    _Converter_IDFromPatent_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ConvertBatch",
+			Handler:       _Converter_ConvertBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "patentes.proto",
+}
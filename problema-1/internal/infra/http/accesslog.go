@@ -0,0 +1,159 @@
+package http_adapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+type ctxKey int
+
+const requestInfoKey ctxKey = iota
+
+// requestInfo lo guarda en el contexto el access log y lo completan los middlewares internos, como
+// la autenticacion, asi la linea del access log tiene la informacion de todo el request.
+type requestInfo struct {
+	id        string
+	principal *Principal
+	// code es el codigo del problem si el request respondio un error
+	code string
+	// lang es el idioma de los mensajes de error, segun Accept-Language
+	lang string
+}
+
+func infoFrom(ctx context.Context) *requestInfo {
+	info, _ := ctx.Value(requestInfoKey).(*requestInfo)
+	return info
+}
+
+// withRequestInfo retorna el requestInfo del request, creandolo si ningun middleware lo creo antes.
+func withRequestInfo(r *http.Request) (*http.Request, *requestInfo) {
+	if info := infoFrom(r.Context()); info != nil {
+		return r, info
+	}
+	info := &requestInfo{}
+	return r.WithContext(context.WithValue(r.Context(), requestInfoKey, info)), info
+}
+
+// RequestIDFrom retorna el id del request guardado en el contexto por el access log.
+func RequestIDFrom(ctx context.Context) string {
+	if info := infoFrom(ctx); info != nil {
+		return info.id
+	}
+	return ""
+}
+
+// contextHandler agrega a cada linea de log los valores del request guardados en el contexto, para
+// que funcione hay que loguear con los metodos *Context de slog.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (c contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if info := infoFrom(ctx); info != nil {
+		if info.id != "" {
+			record.AddAttrs(slog.String("request_id", info.id))
+		}
+		if p := info.principal; p != nil {
+			record.AddAttrs(slog.Group("principal",
+				slog.String("method", p.Method),
+				slog.String("id", p.ID),
+				slog.String("label", p.Label),
+			))
+		}
+	}
+	return c.Handler.Handle(ctx, record)
+}
+
+func (c contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{c.Handler.WithAttrs(attrs)}
+}
+
+func (c contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{c.Handler.WithGroup(name)}
+}
+
+// validRequestID evita propagar ids de clientes que rompan los logs o sean muy largos.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSampling lee una lista "PATRON=TASA,..." con la fraccion de requests exitosos a loguear por
+// ruta, por ejemplo "GET /healthcheck=0.01". Los patrones son los mismos registrados en SetRoutes.
+func parseSampling(value string) (map[string]float64, error) {
+	sampling := map[string]float64{}
+	if strings.TrimSpace(value) == "" {
+		return sampling, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		pattern, rateStr, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("log sample: %q must have the form PATTERN=RATE", item)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("log sample: rate for %q must be between 0 and 1", pattern)
+		}
+		sampling[strings.TrimSpace(pattern)] = rate
+	}
+	return sampling, nil
+}
+
+// accessLog asigna un id al request (o propaga el de X-Request-ID) y al terminar loguea el status,
+// bytes y duracion. Las rutas con sampling solo loguean esa fraccion de los requests exitosos, los
+// errores siempre se loguean.
+func (h *HTTP) accessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		r, info := withRequestInfo(r)
+		info.id = id
+		info.lang = negotiateLang(r.Header.Get("Accept-Language"))
+
+		rw := newResponseWriter(w)
+		next.ServeHTTP(rw, r)
+
+		// r.Pattern lo completa el mux al enrutar el request
+		route := r.Pattern
+		h.publishEvent(r, rw.status, start)
+		if rate, ok := h.logSampling[route]; ok && rw.status < 400 && rand.Float64() >= rate {
+			return
+		}
+
+		// un deadline vencido responde 503 pero no es una falla del servicio
+		level := slog.LevelInfo
+		if rw.status >= 500 && info.code != CodeTimeout {
+			level = slog.LevelError
+		}
+		h.logger.LogAttrs(r.Context(), level, "Request served",
+			slog.String("method", r.Method),
+			slog.String("route", route),
+			slog.String("url", r.URL.String()),
+			slog.Int("status", rw.status),
+			slog.Int("bytes", rw.bytes),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.String("user_agent", r.UserAgent()),
+		)
+	})
+}
@@ -0,0 +1,112 @@
+package http_adapter
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestHTTP(logs io.Writer) *HTTP {
+	h := &HTTP{
+		logger: slog.New(contextHandler{slog.NewJSONHandler(logs, nil)}),
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /ok", func(w http.ResponseWriter, r *http.Request) {
+		h.logger.InfoContext(r.Context(), "Inside handler")
+		io.WriteString(w, "hello")
+	})
+	h.mux.HandleFunc("GET /fail", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad", http.StatusBadRequest)
+	})
+	return h
+}
+
+func TestAccessLog(t *testing.T) {
+	logs := &strings.Builder{}
+	h := newTestHTTP(logs)
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name        string
+		path        string
+		requestID   string
+		propagated  bool
+		status      float64
+		bytes       float64
+		handlerLogs bool
+	}{
+		{"generates request id", "/ok", "", false, 200, 5, true},
+		{"propagates request id", "/ok", "abc-123", true, 200, 5, true},
+		{"replaces invalid request id", "/ok", "bad id\n", false, 200, 5, true},
+		{"logs client errors", "/fail", "", false, 400, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.requestID != "" {
+				req.Header.Set(requestIDHeader, tt.requestID)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			id := rec.Header().Get(requestIDHeader)
+			if id == "" {
+				t.Fatalf("Expected %s response header", requestIDHeader)
+			}
+			if tt.propagated && id != tt.requestID {
+				t.Errorf("Expected request id %q, got %q", tt.requestID, id)
+			}
+			if !tt.propagated && id == tt.requestID {
+				t.Errorf("Expected a generated request id, got %q", id)
+			}
+
+			entry := findLog(t, logs.String(), "Request served")
+			if entry["request_id"] != id {
+				t.Errorf("Expected request_id %q in access log, got %v", id, entry["request_id"])
+			}
+			if entry["status"] != tt.status || entry["bytes"] != tt.bytes {
+				t.Errorf("Expected status %v and bytes %v, got %v and %v", tt.status, tt.bytes, entry["status"], entry["bytes"])
+			}
+			if entry["route"] != "GET "+tt.path {
+				t.Errorf("Expected route %q, got %v", "GET "+tt.path, entry["route"])
+			}
+			if tt.handlerLogs {
+				if inner := findLog(t, logs.String(), "Inside handler"); inner["request_id"] != id {
+					t.Errorf("Expected request_id %q in handler log, got %v", id, inner["request_id"])
+				}
+			}
+		})
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	logs := &strings.Builder{}
+	h := newTestHTTP(logs)
+	sampling, err := parseSampling("GET /ok=0, GET /fail=0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h.logSampling = sampling
+	h.SetMiddlewares()
+
+	h.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if strings.Contains(logs.String(), "Request served") {
+		t.Errorf("Expected successful request to be sampled out, got %q", logs.String())
+	}
+
+	h.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+	if !strings.Contains(logs.String(), "Request served") {
+		t.Errorf("Expected errors to always be logged")
+	}
+
+	for _, invalid := range []string{"GET /ok", "GET /ok=2", "GET /ok=x"} {
+		if _, err := parseSampling(invalid); err == nil {
+			t.Errorf("Expected error for %q, got nil", invalid)
+		}
+	}
+}
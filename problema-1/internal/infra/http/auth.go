@@ -0,0 +1,214 @@
+package http_adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/jwtauth"
+)
+
+// Scopes que exigen las rutas, apikey.ScopeAdmin incluye a todos.
+const (
+	ScopeConvertRead = "convert:read"
+	ScopeAdmin       = apikey.ScopeAdmin
+)
+
+const (
+	CodeUnauthorized = "unauthorized"
+	CodeForbidden    = "forbidden"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+// Principal es quien hace el request una vez autenticado.
+type Principal struct {
+	// Method indica como se autentico, api_key o jwt.
+	Method string
+	ID     string
+	Label  string
+	Scopes []string
+	// Claims son los claims del token cuando Method es jwt.
+	Claims jwtauth.Claims
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// key identifica al principal en los limites, la misma credencial comparte los limites en http y
+// gRPC.
+func (p *Principal) key() string {
+	return p.Method + ":" + p.ID
+}
+
+// PrincipalFrom retorna el principal autenticado del request, nil si es anonimo.
+func PrincipalFrom(ctx context.Context) *Principal {
+	if info := infoFrom(ctx); info != nil {
+		return info.principal
+	}
+	return nil
+}
+
+// authEnabled es falso si no se configuro ninguna forma de autenticacion, en ese caso todas las
+// rutas son anonimas como antes.
+func (h *HTTP) authEnabled() bool {
+	return h.keys != nil || h.jwt != nil || h.mtls
+}
+
+// credentials retorna la api key del header X-API-Key o el token del header Authorization: Bearer,
+// que puede ser una api key o un JWT.
+func credentials(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+// authenticate identifica al cliente si envia credenciales y lo deja en el contexto. Un request sin
+// credenciales sigue como anonimo y es authorize quien decide si la ruta lo permite, pero
+// credenciales invalidas se rechazan de inmediato. Con mTLS el certificado de cliente identifica al
+// request salvo que ademas envie una api key o JWT.
+//
+// rateLimit va despues porque limita por credencial, asi que las credenciales invalidas se limitan
+// aca por IP con authFailures: una IP que agoto sus intentos recibe 429 sin que se validen sus
+// credenciales.
+func (h *HTTP) authenticate(next http.Handler) http.Handler {
+	if !h.authEnabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := clientCertPrincipal(r)
+		if token := credentials(r); token != "" {
+			key := "ip:" + h.clientIP(r)
+			if h.authFailures != nil {
+				if wait, blocked := h.authFailures.Blocked(key, time.Now()); blocked {
+					w.Header().Set("Retry-After", seconds(wait))
+					h.writeProblem(w, r, http.StatusTooManyRequests, CodeRateLimited, "too many failed authentications")
+					return
+				}
+			}
+			var detail string
+			principal, detail = h.principalFromToken(r.Context(), token)
+			if principal == nil {
+				if h.authFailures != nil {
+					h.authFailures.Allow(key, time.Now())
+				}
+				h.unauthorized(w, r, detail)
+				return
+			}
+		}
+		if principal == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r, info := withRequestInfo(r)
+		info.principal = principal
+		next.ServeHTTP(w, r)
+	})
+}
+
+// principalFromToken valida una api key o JWT, si no es valida retorna nil y el motivo para el
+// cliente. Los tokens con el prefijo de las api keys no se intentan como JWT.
+func (h *HTTP) principalFromToken(ctx context.Context, token string) (*Principal, string) {
+	if strings.HasPrefix(token, apikey.Prefix) || h.jwt == nil {
+		return h.principalFromAPIKey(ctx, token)
+	}
+	return h.principalFromJWT(token)
+}
+
+func (h *HTTP) principalFromAPIKey(ctx context.Context, token string) (*Principal, string) {
+	if h.keys == nil {
+		return nil, "api keys are not enabled"
+	}
+	key, err := h.keys.Authenticate(token, time.Now())
+	if err != nil {
+		detail := "invalid api key"
+		switch {
+		case errors.Is(err, apikey.ErrExpired):
+			detail = "api key expired"
+		case errors.Is(err, apikey.ErrRevoked):
+			detail = "api key revoked"
+		case !errors.Is(err, apikey.ErrInvalidKey):
+			h.logger.ErrorContext(ctx, "Failed to authenticate api key", "error", err.Error())
+		}
+		return nil, detail
+	}
+	return &Principal{
+		Method: "api_key",
+		ID:     key.ID,
+		Label:  key.Label,
+		Scopes: key.Scopes,
+	}, ""
+}
+
+// principalFromJWT toma los scopes de los claims scope o scp.
+func (h *HTTP) principalFromJWT(token string) (*Principal, string) {
+	claims, err := h.jwt.Verify(token, time.Now())
+	if err != nil {
+		return nil, err.Error()
+	}
+	label := claims.String("client_id")
+	if label == "" {
+		label = claims.String("iss")
+	}
+	return &Principal{
+		Method: "jwt",
+		ID:     claims.String("sub"),
+		Label:  label,
+		Scopes: append(claims.Strings("scope"), claims.Strings("scp")...),
+		Claims: claims,
+	}, ""
+}
+
+// authorize exige que el request este autenticado y tenga el scope, se aplica por ruta en SetRoutes.
+func (h *HTTP) authorize(scope string, handler http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.authEnabled() {
+			handler(w, r)
+			return
+		}
+		principal := PrincipalFrom(r.Context())
+		if principal == nil {
+			h.unauthorized(w, r, "credentials are required")
+			return
+		}
+		// r.Pattern ya lo completo el mux
+		if detail := h.denied(principal, r.Pattern, scope); detail != "" {
+			h.writeProblem(w, r, http.StatusForbidden, CodeForbidden, detail)
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// denied decide si el principal puede usar la ruta, con politica los JWT se autorizan por ruta segun
+// sus claims y los demas por el scope. Retorna el motivo del rechazo, vacio si se permite.
+func (h *HTTP) denied(principal *Principal, pattern string, scope string) string {
+	if principal.Method == "jwt" && h.policy != nil {
+		// las reglas de las politicas anteriores a /v1 nombran la ruta sin version, valen para las dos
+		alias, hasAlias := h.routeAliases[pattern]
+		if !h.policy.Allows(pattern, principal.Claims) && (!hasAlias || !h.policy.Allows(alias, principal.Claims)) {
+			return "policy does not allow " + pattern
+		}
+		return ""
+	}
+	if !principal.HasScope(scope) {
+		return "missing scope " + scope
+	}
+	return ""
+}
+
+func (h *HTTP) unauthorized(w http.ResponseWriter, r *http.Request, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="patentes"`)
+	h.writeProblem(w, r, http.StatusUnauthorized, CodeUnauthorized, detail)
+}
@@ -0,0 +1,217 @@
+package http_adapter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/jwtauth"
+)
+
+func TestAPIKeyAuth(t *testing.T) {
+	keys, err := apikey.Open(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reader, _, err := keys.Create("lector", []string{ScopeConvertRead}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	admin, _, err := keys.Create("operaciones", []string{ScopeAdmin}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expired, _, err := keys.Create("vencida", []string{ScopeConvertRead}, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	logs := &strings.Builder{}
+	h := newTestHTTP(logs)
+	h.app = &app.App{}
+	h.keys = keys
+	h.metrics = newHTTPMetrics(func() map[string]uint64 { return nil })
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		path         string
+		header       string
+		value        string
+		expectedCode int
+		expectedErr  string
+	}{
+		{"anonymous conversion", "/patente/1", "", "", http.StatusUnauthorized, CodeUnauthorized},
+		{"anonymous healthcheck", "/healthcheck", "", "", http.StatusOK, ""},
+		{"reader with header", "/patente/1", apiKeyHeader, reader, http.StatusOK, ""},
+		{"reader with bearer", "/id/AAAA000", "Authorization", "Bearer " + reader, http.StatusOK, ""},
+		{"reader without admin scope", "/metrics", apiKeyHeader, reader, http.StatusForbidden, CodeForbidden},
+		{"admin has every scope", "/metrics", apiKeyHeader, admin, http.StatusOK, ""},
+		{"invalid key", "/healthcheck", apiKeyHeader, "pk_nope_nope", http.StatusUnauthorized, CodeUnauthorized},
+		{"expired key", "/patente/1", apiKeyHeader, expired, http.StatusUnauthorized, CodeUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if tt.expectedErr != "" {
+				var body map[string]any
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Fatalf("Failed to decode body: %v", err)
+				}
+				if body["code"] != tt.expectedErr {
+					t.Errorf("Expected code %q, got %v", tt.expectedErr, body["code"])
+				}
+			}
+			if tt.expectedCode == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("Expected WWW-Authenticate header on 401")
+			}
+		})
+	}
+
+	// el access log atribuye el request a la key
+	logs.Reset()
+	req := httptest.NewRequest(http.MethodGet, "/patente/1", nil)
+	req.Header.Set(apiKeyHeader, reader)
+	h.Handler().ServeHTTP(httptest.NewRecorder(), req)
+	entry := findLog(t, logs.String(), "Request served")
+	principal, _ := entry["principal"].(map[string]any)
+	if principal["label"] != "lector" || principal["method"] != "api_key" {
+		t.Errorf("Expected principal lector in access log, got %v", entry["principal"])
+	}
+}
+
+func signHS256(secret []byte, claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTAuth(t *testing.T) {
+	secret := []byte("01234567890123456789012345678901")
+	key, err := jwtauth.NewHMACKey("", secret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verifier, err := jwtauth.NewVerifier(jwtauth.Options{Keys: []jwtauth.Key{key}, Audience: "patentes"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	policyContent := `{"rules": [
+		{"routes": ["GET /patente/{id}"], "claims": {"groups": "kiosko"}},
+		{"routes": ["*"], "claims": {"groups": "ops"}}
+	]}`
+	if err := os.WriteFile(policyPath, []byte(policyContent), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	policy, err := jwtauth.LoadPolicy(policyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	kiosko := signHS256(secret, map[string]any{"sub": "k1", "aud": "patentes", "exp": exp, "groups": []string{"kiosko"}})
+	ops := signHS256(secret, map[string]any{"sub": "o1", "aud": "patentes", "exp": exp, "groups": []string{"ops"}})
+	otherAudience := signHS256(secret, map[string]any{"sub": "k1", "aud": "otra", "exp": exp, "groups": []string{"ops"}})
+	withScope := signHS256(secret, map[string]any{"sub": "s1", "aud": "patentes", "exp": exp, "scope": "convert:read"})
+
+	tests := []struct {
+		name         string
+		policy       *jwtauth.Policy
+		path         string
+		token        string
+		expectedCode int
+	}{
+		{"policy allows route", policy, "/patente/1", kiosko, http.StatusOK},
+		{"policy denies route", policy, "/id/AAAA000", kiosko, http.StatusForbidden},
+		{"legacy rule allows versioned route", policy, "/v1/patente/1", kiosko, http.StatusOK},
+		{"legacy rule denies versioned route", policy, "/v1/id/AAAA000", kiosko, http.StatusForbidden},
+		{"policy wildcard", policy, "/metrics", ops, http.StatusOK},
+		{"invalid audience", policy, "/patente/1", otherAudience, http.StatusUnauthorized},
+		{"garbage token", policy, "/patente/1", "a.b.c", http.StatusUnauthorized},
+		{"scopes without policy", nil, "/id/AAAA000", withScope, http.StatusOK},
+		{"missing scope without policy", nil, "/metrics", withScope, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHTTP(&strings.Builder{})
+			h.app = &app.App{}
+			h.jwt = verifier
+			h.policy = tt.policy
+			h.metrics = newHTTPMetrics(func() map[string]uint64 { return nil })
+			h.SetRoutes()
+			h.SetMiddlewares()
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestJWTPolicyRoutes(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.SetRoutes()
+
+	tests := []struct {
+		name    string
+		routes  string
+		wantErr bool
+	}{
+		{"versioned route", `"GET /v1/patente/{id}"`, false},
+		{"legacy route", `"GET /patente/{id}"`, false},
+		{"wildcard", `"*"`, false},
+		{"unknown route", `"GET /v2/patente/{id}"`, true},
+		{"path without method", `"/v1/patente/{id}"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			content := `{"rules": [{"routes": [` + tt.routes + `], "claims": {"groups": "kiosko"}}]}`
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			policy, err := jwtauth.LoadPolicy(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			err = policy.Check(h.routePatterns())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package http_adapter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCacheMaxAge es un año, el maximo que recomienda RFC 9111 para respuestas que no cambian.
+const defaultCacheMaxAge = 365 * 24 * time.Hour
+
+// etag calcula un ETag fuerte del contenido, incluye el content type para que cada representacion
+// del mismo recurso tenga el suyo.
+func etag(contentType string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(contentType))
+	sum.Write([]byte{0})
+	sum.Write(body)
+	return `"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`
+}
+
+// matchesETag implementa If-None-Match, que usa comparacion debil: W/"x" coincide con "x".
+func matchesETag(ifNoneMatch string, tag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCached responde una conversion exitosa. Las conversiones son funciones puras, asi que la
+// respuesta se puede cachear como inmutable y un If-None-Match que coincide responde 304 sin body.
+// vary son los headers del request que cambian la representacion. Con autenticacion la respuesta
+// es private para que un CDN no se la entregue a clientes sin credenciales.
+func (h *HTTP) writeCached(w http.ResponseWriter, r *http.Request, contentType string, body []byte, vary ...string) {
+	maxAge := h.cacheMaxAge
+	if maxAge == 0 {
+		maxAge = defaultCacheMaxAge
+	}
+	visibility := "public"
+	if h.authEnabled() {
+		visibility = "private"
+	}
+
+	tag := etag(contentType, body)
+	header := w.Header()
+	header.Set("ETag", tag)
+	header.Set("Cache-Control", visibility+", max-age="+strconv.Itoa(int(maxAge.Seconds()))+", immutable")
+	for _, name := range vary {
+		header.Add("Vary", name)
+	}
+
+	if matchesETag(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Length", strconv.Itoa(len(body)))
+	w.Write(body)
+}
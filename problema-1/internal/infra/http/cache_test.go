@@ -0,0 +1,78 @@
+package http_adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+func TestConversionCaching(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	get := func(path string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("/patente/1", "")
+	tag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(tag, `"`) {
+		t.Fatalf("Expected 200 with a strong ETag, got %d %q", first.Code, tag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+		t.Errorf("Expected immutable Cache-Control, got %q", got)
+	}
+	if get("/patente/1", "").Header().Get("ETag") != tag {
+		t.Errorf("Expected the same ETag for the same conversion")
+	}
+	if get("/patente/2", "").Header().Get("ETag") == tag {
+		t.Errorf("Expected a different ETag for a different conversion")
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		ifNoneMatch  string
+		expectedCode int
+	}{
+		{"matching etag", "/patente/1", tag, http.StatusNotModified},
+		{"weak matching etag", "/patente/1", "W/" + tag, http.StatusNotModified},
+		{"etag in list", "/patente/1", `"other", ` + tag, http.StatusNotModified},
+		{"any etag", "/patente/1", "*", http.StatusNotModified},
+		{"stale etag", "/patente/1", `"other"`, http.StatusOK},
+		{"etag of other resource", "/patente/2", tag, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(tt.path, tt.ifNoneMatch)
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if tt.expectedCode == http.StatusNotModified {
+				if rec.Body.Len() != 0 {
+					t.Errorf("Expected empty body on 304, got %q", rec.Body.String())
+				}
+				if rec.Header().Get("ETag") != tag || rec.Header().Get("Cache-Control") == "" {
+					t.Errorf("Expected ETag and Cache-Control on 304")
+				}
+			}
+		})
+	}
+
+	// los errores no se cachean
+	if rec := get("/patente/-1", ""); rec.Header().Get("Cache-Control") != "" || rec.Header().Get("ETag") != "" {
+		t.Errorf("Expected errors without cache headers")
+	}
+}
@@ -0,0 +1,64 @@
+package http_adapter
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Tipos mayores de CBOR (RFC 8949) que usan las respuestas.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborText   = 3
+	cborMap    = 5
+)
+
+// encodeCBOR codifica la respuesta como un mapa CBOR con los campos en orden. Solo soporta los tipos
+// que tienen las respuestas: enteros, strings y booleanos.
+func encodeCBOR(v response) ([]byte, error) {
+	fields := v.fields()
+	buf := cborHead(nil, cborMap, uint64(len(fields)))
+	for _, f := range fields {
+		buf = cborHead(buf, cborText, uint64(len(f.name)))
+		buf = append(buf, f.name...)
+
+		switch value := f.value.(type) {
+		case string:
+			buf = cborHead(buf, cborText, uint64(len(value)))
+			buf = append(buf, value...)
+		case uint:
+			buf = cborHead(buf, cborUint, uint64(value))
+		case int:
+			if value < 0 {
+				buf = cborHead(buf, cborNegInt, uint64(-1-value))
+			} else {
+				buf = cborHead(buf, cborUint, uint64(value))
+			}
+		case bool:
+			if value {
+				buf = append(buf, 0xf5)
+			} else {
+				buf = append(buf, 0xf4)
+			}
+		default:
+			return nil, fmt.Errorf("cbor: unsupported type %T in field %s", f.value, f.name)
+		}
+	}
+	return buf, nil
+}
+
+// cborHead agrega el byte inicial con el tipo mayor y el argumento en la forma mas corta.
+func cborHead(buf []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(buf, major|byte(arg))
+	case arg <= 0xff:
+		return append(buf, major|24, byte(arg))
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(buf, major|27), arg)
+}
@@ -0,0 +1,83 @@
+package http_adapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/pkgs/client"
+)
+
+// Tests de contrato de pkgs/client contra el servidor, si cambian las rutas, las respuestas o los
+// codigos de error estos tests fallan antes que los consumidores.
+
+func TestClientContract(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.SetRoutes()
+	h.SetMiddlewares()
+	server := httptest.NewServer(h.Handler())
+	defer server.Close()
+
+	c, err := client.New(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	patente, err := c.PatentFromID(ctx, 2)
+	if err != nil || patente != "AAAA001" {
+		t.Errorf("Expected AAAA001, got %q %v", patente, err)
+	}
+	id, err := c.IDFromPatent(ctx, "AAAA001")
+	if err != nil || id != 2 {
+		t.Errorf("Expected 2, got %d %v", id, err)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected error
+	}{
+		{"id out of range", func() error { _, err := c.PatentFromID(ctx, 0); return err }, client.ErrInvalidRange},
+		{"bad format", func() error { _, err := c.IDFromPatent(ctx, "AA/1"); return err }, client.ErrBadFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, err)
+			}
+			var apiErr *client.Error
+			errors.As(err, &apiErr)
+			if apiErr.Status != http.StatusBadRequest || apiErr.RequestID == "" {
+				t.Errorf("Expected status 400 with request id, got %+v", apiErr)
+			}
+		})
+	}
+}
+
+func TestClientErrorCodes(t *testing.T) {
+	codes := map[string]string{
+		app.CodeInvalidRange: client.CodeInvalidRange,
+		app.CodeEmpty:        client.CodeEmpty,
+		app.CodeBadFormat:    client.CodeBadFormat,
+		CodeInvalidID:        client.CodeInvalidID,
+		CodeUnauthorized:     client.CodeUnauthorized,
+		CodeForbidden:        client.CodeForbidden,
+		CodeRateLimited:      client.CodeRateLimited,
+		CodeQuotaExceeded:    client.CodeQuotaExceeded,
+		CodeNotFound:         client.CodeNotFound,
+		CodeInternal:         client.CodeInternal,
+	}
+	for server, sdk := range codes {
+		if server != sdk {
+			t.Errorf("Expected client code %q, got %q", server, sdk)
+		}
+	}
+}
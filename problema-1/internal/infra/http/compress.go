@@ -0,0 +1,264 @@
+package http_adapter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const CodeUnsupportedEncoding = "unsupported_encoding"
+
+// compressors son las codificaciones soportadas en orden de preferencia. deflate en HTTP es el
+// formato zlib (RFC 9110). zstd no esta en la libreria estandar, asi que no se ofrece.
+var compressors = []struct {
+	name string
+	pool *sync.Pool
+}{
+	{"gzip", &sync.Pool{New: func() any { return gzip.NewWriter(nil) }}},
+	{"deflate", &sync.Pool{New: func() any { return zlib.NewWriter(nil) }}},
+}
+
+// compressWriter es el writer comun de gzip y zlib, Reset permite reutilizarlos desde el pool.
+type compressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressible reporta si vale la pena comprimir el content type, las imagenes y binarios ya
+// comprimidos no se achican.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/cbor":
+		return true
+	}
+	return false
+}
+
+// chooseEncoding elige la codificacion segun Accept-Encoding, vacio significa sin comprimir.
+func chooseEncoding(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	for _, c := range compressors {
+		q := 0.0
+		specific := false
+		for _, item := range strings.Split(acceptEncoding, ",") {
+			// Accept-Encoding tiene la misma forma que Accept, el parser de media types no sirve
+			name, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != c.name && (name != "*" || specific) {
+				continue
+			}
+			specific = name == c.name
+			q = 1
+			if _, value, found := strings.Cut(params, "q="); found {
+				q = parseQuality(value)
+			}
+		}
+		if q > bestQ {
+			best, bestQ = c.name, q
+		}
+	}
+	return best
+}
+
+func compressorPool(name string) *sync.Pool {
+	for _, c := range compressors {
+		if c.name == name {
+			return c.pool
+		}
+	}
+	return nil
+}
+
+// compress comprime las respuestas de al menos compressMinSize bytes. La decision se toma al juntar
+// ese tamaño o al terminar, por eso las respuestas chicas se escriben sin comprimir y con su
+// Content-Length. El ETag pasa a ser debil porque el contenido comprimido no es identico byte a byte.
+func (h *HTTP) compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := chooseEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressResponseWriter{
+			ResponseWriter: w,
+			pool:           compressorPool(encoding),
+			encoding:       encoding,
+			minSize:        h.compressMinSize,
+			status:         http.StatusOK,
+		}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+type compressResponseWriter struct {
+	http.ResponseWriter
+	pool     *sync.Pool
+	encoding string
+	minSize  int
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	writer      compressWriter
+}
+
+func (cw *compressResponseWriter) WriteHeader(status int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.status = status
+	cw.wroteHeader = true
+	// los informativos no son la respuesta final y 204/304 no tienen body
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.buf = append(cw.buf, b...)
+		if len(cw.buf) < cw.minSize {
+			return len(b), nil
+		}
+		if err := cw.decide(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if cw.writer != nil {
+		return cw.writer.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// decide escribe el header, con Content-Encoding si se comprime, y lo que estaba en el buffer.
+func (cw *compressResponseWriter) decide(compress bool) error {
+	cw.decided = true
+	header := cw.Header()
+	if compress && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		if tag := header.Get("ETag"); strings.HasPrefix(tag, `"`) {
+			header.Set("ETag", "W/"+tag)
+		}
+		cw.writer = cw.pool.Get().(compressWriter)
+		cw.writer.Reset(cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.writer != nil {
+		_, err = cw.writer.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// Flush decide con lo que haya en el buffer, asi los streams no quedan esperando el tamaño minimo.
+func (cw *compressResponseWriter) Flush() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			cw.WriteHeader(http.StatusOK)
+		}
+		cw.decide(len(cw.buf) >= cw.minSize)
+	}
+	if cw.writer != nil {
+		cw.writer.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *compressResponseWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			// el handler no escribio nada, se responde el 200 implicito igual que net/http
+			cw.WriteHeader(http.StatusOK)
+		}
+		cw.decide(false)
+	}
+	if cw.writer != nil {
+		cw.writer.Close()
+		cw.writer.Reset(io.Discard)
+		cw.pool.Put(cw.writer)
+		cw.writer = nil
+	}
+}
+
+// Unwrap permite a http.ResponseController llegar al writer original.
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Hijack evita que close escriba una respuesta en una conexion tomada por un WebSocket.
+func (cw *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(cw.ResponseWriter).Hijack()
+	if err == nil {
+		cw.wroteHeader = true
+		cw.decided = true
+	}
+	return conn, brw, err
+}
+
+// decompress descomprime los bodies con Content-Encoding gzip o deflate. Va antes de limitBody para
+// que el limite se aplique al body descomprimido y un archivo chico no se pueda expandir sin limite.
+func (h *HTTP) decompress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+		if encoding == "" || encoding == "identity" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body io.ReadCloser
+		var err error
+		switch encoding {
+		case "gzip":
+			body, err = gzip.NewReader(r.Body)
+		case "deflate":
+			body, err = zlib.NewReader(r.Body)
+		default:
+			w.Header().Set("Accept-Encoding", "gzip, deflate")
+			h.writeProblem(w, r, http.StatusUnsupportedMediaType, CodeUnsupportedEncoding,
+				"request body encoding "+encoding+" is not supported")
+			return
+		}
+		if err != nil {
+			h.writeProblem(w, r, http.StatusBadRequest, CodeUnsupportedEncoding, "invalid "+encoding+" body")
+			return
+		}
+		defer body.Close()
+
+		r.Body = body
+		r.ContentLength = -1
+		r.Header.Del("Content-Encoding")
+		r.Header.Del("Content-Length")
+		next.ServeHTTP(w, r)
+	})
+}
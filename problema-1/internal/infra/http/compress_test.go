@@ -0,0 +1,156 @@
+package http_adapter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	large := strings.Repeat("patente ", 512)
+	h := newTestHTTP(&strings.Builder{})
+	h.compressMinSize = 1024
+	h.mux.HandleFunc("GET /large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"abc"`)
+		io.WriteString(w, large)
+	})
+	h.mux.HandleFunc("GET /image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		io.WriteString(w, large)
+	})
+	h.mux.HandleFunc("GET /empty", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		encoding       string
+		etag           string
+	}{
+		{"gzip", "/large", "gzip", "gzip", `W/"abc"`},
+		{"deflate", "/large", "deflate", "deflate", `W/"abc"`},
+		{"prefers gzip", "/large", "deflate, gzip", "gzip", `W/"abc"`},
+		{"quality", "/large", "gzip;q=0.2, deflate;q=0.8", "deflate", `W/"abc"`},
+		{"wildcard", "/large", "*", "gzip", `W/"abc"`},
+		{"excluded by q=0", "/large", "gzip;q=0, deflate;q=0", "", `"abc"`},
+		{"unsupported encoding", "/large", "br, zstd", "", `"abc"`},
+		{"without accept encoding", "/large", "", "", `"abc"`},
+		{"small response", "/ok", "gzip", "", ""},
+		{"not compressible", "/image", "gzip", "", ""},
+		{"no content", "/empty", "gzip", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tt.encoding, got)
+			}
+			if !strings.Contains(strings.Join(rec.Header().Values("Vary"), ","), "Accept-Encoding") {
+				t.Errorf("Expected Vary: Accept-Encoding, got %v", rec.Header().Values("Vary"))
+			}
+			if got := rec.Header().Get("ETag"); got != tt.etag {
+				t.Errorf("Expected ETag %s, got %s", tt.etag, got)
+			}
+
+			var body io.Reader = rec.Body
+			switch tt.encoding {
+			case "gzip":
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("Invalid gzip body: %v", err)
+				}
+				body = zr
+			case "deflate":
+				zr, err := zlib.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("Invalid deflate body: %v", err)
+				}
+				body = zr
+			}
+			content, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.path == "/large" && string(content) != large {
+				t.Errorf("Expected original body after decoding")
+			}
+		})
+	}
+}
+
+func TestRequestDecompression(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.maxBody = 4096
+	h.mux.HandleFunc("POST /upload", func(w http.ResponseWriter, r *http.Request) {
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.writeAppError(w, r, err)
+			return
+		}
+		w.Write(content)
+	})
+	h.SetMiddlewares()
+
+	compress := func(encoding string, content string) []byte {
+		buf := &bytes.Buffer{}
+		var zw io.WriteCloser
+		if encoding == "gzip" {
+			zw = gzip.NewWriter(buf)
+		} else {
+			zw = zlib.NewWriter(buf)
+		}
+		io.WriteString(zw, content)
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name         string
+		encoding     string
+		body         []byte
+		expectedCode int
+		expectedBody string
+	}{
+		{"plain", "", []byte("AAAA000"), http.StatusOK, "AAAA000"},
+		{"gzip", "gzip", compress("gzip", "AAAA000"), http.StatusOK, "AAAA000"},
+		{"deflate", "deflate", compress("deflate", "AAAA000"), http.StatusOK, "AAAA000"},
+		{"invalid gzip", "gzip", []byte("nope"), http.StatusBadRequest, ""},
+		{"unsupported encoding", "br", []byte("nope"), http.StatusUnsupportedMediaType, ""},
+		// el limite aplica al body descomprimido
+		{"decompression bomb", "gzip", compress("gzip", strings.Repeat("a", 1<<20)), http.StatusRequestEntityTooLarge, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if tt.expectedBody != "" && rec.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package http_adapter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docopt/docopt-go"
+)
+
+// exposedHeaders son los headers de respuesta que el navegador deja leer al javascript.
+var exposedHeaders = []string{
+	requestIDHeader,
+	"RateLimit-Limit",
+	"RateLimit-Remaining",
+	"RateLimit-Reset",
+	"Retry-After",
+	"X-Quota-Limit",
+	"X-Quota-Remaining",
+}
+
+// corsConfig define que origenes pueden llamar a la api desde el navegador. Los origenes pueden ser
+// exactos (https://tools.example.com), con comodin de subdominio (https://*.example.com) o "*".
+type corsConfig struct {
+	origins     []string
+	methods     []string
+	headers     []string
+	credentials bool
+	maxAge      time.Duration
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCORS retorna nil si no se configuraron origenes.
+func parseCORS(opts docopt.Opts) (*corsConfig, error) {
+	origins, _ := opts.String("--cors-origins")
+	if strings.TrimSpace(origins) == "" {
+		return nil, nil
+	}
+	methods, _ := opts.String("--cors-methods")
+	headers, _ := opts.String("--cors-headers")
+	credentials, _ := opts.Bool("--cors-credentials")
+	maxAgeStr, _ := opts.String("--cors-max-age")
+	maxAge, err := time.ParseDuration(maxAgeStr)
+	if err != nil || maxAge < 0 {
+		return nil, fmt.Errorf("cors: invalid --cors-max-age %q", maxAgeStr)
+	}
+
+	c := &corsConfig{
+		origins:     splitList(origins),
+		credentials: credentials,
+		maxAge:      maxAge,
+	}
+	for _, method := range splitList(methods) {
+		c.methods = append(c.methods, strings.ToUpper(method))
+	}
+	for _, header := range splitList(headers) {
+		c.headers = append(c.headers, http.CanonicalHeaderKey(header))
+	}
+	for _, origin := range c.origins {
+		if origin == "*" {
+			// con credenciales el navegador no acepta "*" y reflejar cualquier origen seria inseguro
+			if credentials {
+				return nil, errors.New("cors: --cors-credentials cannot be used with the * origin")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			return nil, fmt.Errorf("cors: origin %q must have the form scheme://host[:port]", origin)
+		}
+	}
+	return c, nil
+}
+
+// allowsOrigin compara el header Origin con los origenes configurados.
+func (c *corsConfig) allowsOrigin(origin string) bool {
+	for _, allowed := range c.origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+		scheme, host, found := strings.Cut(allowed, "://*.")
+		if !found {
+			continue
+		}
+		// https://*.example.com acepta https://a.example.com y https://a.b.example.com pero no
+		// https://example.com ni https://evilexample.com
+		prefix := scheme + "://"
+		suffix := "." + host
+		if len(origin) > len(prefix)+len(suffix) &&
+			strings.EqualFold(origin[:len(prefix)], prefix) &&
+			strings.EqualFold(origin[len(origin)-len(suffix):], suffix) &&
+			!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:@") {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPreflight revisa el metodo y los headers que pide el preflight.
+func (c *corsConfig) allowsPreflight(r *http.Request) bool {
+	if !slices.Contains(c.methods, r.Header.Get("Access-Control-Request-Method")) {
+		return false
+	}
+	for _, header := range splitList(r.Header.Get("Access-Control-Request-Headers")) {
+		if !slices.Contains(c.headers, http.CanonicalHeaderKey(header)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cors agrega los headers CORS a las respuestas de origenes permitidos y responde los preflight
+// OPTIONS antes de llegar al mux, que solo tiene rutas GET y responderia 405. Va antes de la
+// autenticacion porque el navegador no envia credenciales en el preflight.
+func (h *HTTP) cors(next http.Handler) http.Handler {
+	if h.corsConfig == nil {
+		return next
+	}
+	c := h.corsConfig
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+		if origin == "" || !c.allowsOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if slices.Contains(c.origins, "*") {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if c.credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			header.Set("Access-Control-Expose-Headers", strings.Join(exposedHeaders, ", "))
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !c.allowsPreflight(r) {
+			// sin los headers Allow-Methods el navegador bloquea el request
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		header.Set("Access-Control-Allow-Methods", strings.Join(c.methods, ", "))
+		if len(c.headers) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(c.headers, ", "))
+		}
+		if c.maxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(c.maxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,154 @@
+package http_adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docopt/docopt-go"
+)
+
+func TestCORS(t *testing.T) {
+	config, err := parseCORS(docopt.Opts{
+		"--cors-origins":     "https://tools.example.com, https://*.internal.example.com",
+		"--cors-methods":     "get",
+		"--cors-headers":     "authorization,x-api-key",
+		"--cors-credentials": true,
+		"--cors-max-age":     "10m",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := newTestHTTP(&strings.Builder{})
+	h.corsConfig = config
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name          string
+		method        string
+		origin        string
+		requestMethod string
+		requestHeader string
+		expectedCode  int
+		allowOrigin   string
+		allowMethods  string
+	}{
+		{"exact origin", http.MethodGet, "https://tools.example.com", "", "", http.StatusOK, "https://tools.example.com", ""},
+		{"wildcard subdomain", http.MethodGet, "https://a.b.internal.example.com", "", "", http.StatusOK, "https://a.b.internal.example.com", ""},
+		{"wildcard does not match apex", http.MethodGet, "https://internal.example.com", "", "", http.StatusOK, "", ""},
+		{"wildcard does not match suffix", http.MethodGet, "https://evilinternal.example.com", "", "", http.StatusOK, "", ""},
+		{"wildcard checks scheme", http.MethodGet, "http://a.internal.example.com", "", "", http.StatusOK, "", ""},
+		{"unknown origin", http.MethodGet, "https://evil.com", "", "", http.StatusOK, "", ""},
+		{"preflight", http.MethodOptions, "https://tools.example.com", "GET", "Authorization", http.StatusNoContent, "https://tools.example.com", "GET"},
+		{"preflight with disallowed method", http.MethodOptions, "https://tools.example.com", "DELETE", "", http.StatusNoContent, "https://tools.example.com", ""},
+		{"preflight with disallowed header", http.MethodOptions, "https://tools.example.com", "GET", "X-Custom", http.StatusNoContent, "https://tools.example.com", ""},
+		{"preflight from unknown origin", http.MethodOptions, "https://evil.com", "GET", "", http.StatusMethodNotAllowed, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/ok", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			if tt.requestHeader != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.requestHeader)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.allowOrigin, got)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Methods"); got != tt.allowMethods {
+				t.Errorf("Expected Access-Control-Allow-Methods %q, got %q", tt.allowMethods, got)
+			}
+			if !strings.Contains(strings.Join(rec.Header().Values("Vary"), ","), "Origin") {
+				t.Errorf("Expected Vary: Origin, got %v", rec.Header().Values("Vary"))
+			}
+			if tt.allowOrigin == "" {
+				return
+			}
+			if rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Errorf("Expected Access-Control-Allow-Credentials true")
+			}
+			if tt.allowMethods != "" && rec.Header().Get("Access-Control-Max-Age") != "600" {
+				t.Errorf("Expected Access-Control-Max-Age 600, got %q", rec.Header().Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+}
+
+func TestParseCORS(t *testing.T) {
+	tests := []struct {
+		name        string
+		origins     string
+		credentials bool
+		expectErr   bool
+	}{
+		{"disabled", "", false, false},
+		{"any origin", "*", false, false},
+		{"any origin with credentials", "*", true, true},
+		{"origin without scheme", "tools.example.com", false, true},
+		{"origin with path", "https://tools.example.com/app", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCORS(docopt.Opts{
+				"--cors-origins":     tt.origins,
+				"--cors-methods":     "GET",
+				"--cors-headers":     "",
+				"--cors-credentials": tt.credentials,
+				"--cors-max-age":     "10m",
+			})
+			if (err != nil) != tt.expectErr {
+				t.Errorf("Expected error %v, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+// con los valores por defecto de --cors-methods y --cors-headers el navegador puede llamar a las
+// rutas POST con un body JSON
+func TestCORSDefaultsAllowPOSTRoutes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	baseURL := setupTestServer(t, ctx, "--cors-origins=https://tools.example.com")
+
+	var posts []string
+	for _, rt := range newTestHTTP(&strings.Builder{}).routes() {
+		if path, ok := strings.CutPrefix(rt.pattern, "POST "); ok {
+			posts = append(posts, path)
+		}
+	}
+	if len(posts) == 0 {
+		t.Fatalf("Expected POST routes")
+	}
+	for _, path := range posts {
+		t.Run(path, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(ctx, http.MethodOptions, baseURL+path, nil)
+			req.Header.Set("Origin", "https://tools.example.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			req.Header.Set("Access-Control-Request-Headers", "content-type,x-api-key")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if got := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(got, http.MethodPost) {
+				t.Errorf("Expected POST allowed, got %q", got)
+			}
+			if got := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(got, "Content-Type") {
+				t.Errorf("Expected Content-Type allowed, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Pagina de documentacion: lee /openapi.json y por cada operacion arma un formulario para probarla.
+"use strict";
+
+const credential = document.getElementById("credential");
+credential.value = sessionStorage.getItem("credential") || "";
+credential.addEventListener("change", () => sessionStorage.setItem("credential", credential.value));
+
+function renderOperation(spec, path, method, op) {
+  const node = document.getElementById("operation").content.cloneNode(true);
+  node.querySelector(".method").textContent = method;
+  node.querySelector(".path").textContent = path;
+  node.querySelector(".summary").textContent = op.summary || "";
+
+  const scopes = (op.security || []).flatMap((s) => s.bearer || []);
+  if (scopes.length > 0) {
+    const scope = document.createElement("span");
+    scope.className = "scope";
+    scope.textContent = " requiere " + scopes.join(", ");
+    node.querySelector("h2").append(scope);
+  }
+
+  const params = node.querySelector(".params");
+  for (const param of (op.parameters || []).filter((p) => p.in === "path")) {
+    const label = document.createElement("label");
+    label.textContent = param.name;
+    label.title = param.description || "";
+    const input = document.createElement("input");
+    input.name = param.name;
+    input.required = true;
+    input.value = param.example ?? "";
+    label.append(input);
+    params.append(label);
+  }
+
+  const select = node.querySelector("select");
+  const ok = op.responses["200"] || {};
+  for (const type of Object.keys(ok.content || {})) {
+    select.append(new Option(type, type));
+  }
+
+  const form = node.querySelector("form");
+  const result = node.querySelector(".result");
+  form.addEventListener("submit", async (event) => {
+    event.preventDefault();
+    let url = path;
+    for (const input of params.querySelectorAll("input")) {
+      url = url.replace("{" + input.name + "}", encodeURIComponent(input.value));
+    }
+    const headers = {};
+    if (select.value) {
+      headers.Accept = select.value;
+    }
+    if (credential.value) {
+      headers.Authorization = "Bearer " + credential.value;
+    }
+
+    result.hidden = false;
+    try {
+      const response = await fetch(url, { method: method.toUpperCase(), headers });
+      const lines = [response.status + " " + response.statusText];
+      for (const [name, value] of response.headers) {
+        lines.push(name + ": " + value);
+      }
+      const type = response.headers.get("Content-Type") || "";
+      const body = type.includes("cbor")
+        ? Array.from(new Uint8Array(await response.arrayBuffer()), (b) => b.toString(16).padStart(2, "0")).join(" ")
+        : await response.text();
+      result.textContent = lines.join("\n") + "\n\n" + body;
+    } catch (err) {
+      result.textContent = String(err);
+    }
+  });
+  return node;
+}
+
+async function main() {
+  const response = await fetch("../openapi.json");
+  const spec = await response.json();
+  document.title = spec.info.title + " - API";
+  document.getElementById("title").textContent = spec.info.title + " " + spec.info.version;
+  document.getElementById("description").textContent = spec.info.description || "";
+
+  const operations = document.getElementById("operations");
+  for (const path of Object.keys(spec.paths).sort()) {
+    for (const [method, op] of Object.entries(spec.paths[path])) {
+      operations.append(renderOperation(spec, path, method, op));
+    }
+  }
+}
+
+main();
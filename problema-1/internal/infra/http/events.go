@@ -0,0 +1,279 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docopt/docopt-go"
+)
+
+// Tipos de los eventos de /events/stream.
+const (
+	eventConversion = "conversion"
+	eventError      = "error"
+)
+
+const (
+	// eventSubBuffer es la cantidad de eventos que puede tener pendientes un cliente, uno que no los
+	// lee a tiempo se desconecta y se recupera reconectando con Last-Event-ID
+	eventSubBuffer = 256
+	// eventRetry es el tiempo en milisegundos que espera EventSource para reconectar
+	eventRetry = 3000
+	// eventWriteTimeout corta el stream de un cliente que no lee
+	eventWriteTimeout = 10 * time.Second
+)
+
+// event es un request servido, lo publica el access log.
+type event struct {
+	ID        uint64    `json:"id"`
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Status    int       `json:"status"`
+	Code      string    `json:"code,omitempty"`
+	Duration  float64   `json:"duration_ms"`
+	RequestID string    `json:"request_id"`
+}
+
+// eventFilter selecciona los eventos de un cliente, un campo vacio acepta todos.
+type eventFilter struct {
+	routes map[string]bool
+	types  map[string]bool
+}
+
+func (f eventFilter) match(ev event) bool {
+	if len(f.types) > 0 && !f.types[ev.Type] {
+		return false
+	}
+	if len(f.routes) > 0 {
+		// se acepta el patron completo o solo su path, "GET /v1/patente/{id}" o "/v1/patente/{id}"
+		_, path, _ := strings.Cut(ev.Route, " ")
+		return f.routes[ev.Route] || f.routes[path]
+	}
+	return true
+}
+
+type eventSub struct {
+	ch     chan event
+	filter eventFilter
+}
+
+// eventBroker reparte los eventos a los clientes conectados y guarda los ultimos en un buffer
+// circular para que un cliente que reconecta reciba los que se perdio. Los ids parten de la hora de
+// inicio en microsegundos, asi despues de reiniciar siguen creciendo y un Last-Event-ID anterior no
+// salta los eventos nuevos.
+type eventBroker struct {
+	heartbeat time.Duration
+
+	mu     sync.Mutex
+	ring   []event
+	next   int
+	lastID uint64
+	subs   map[*eventSub]struct{}
+
+	shutdown  chan struct{}
+	closeOnce sync.Once
+}
+
+func newEventBroker(size int, heartbeat time.Duration) *eventBroker {
+	return &eventBroker{
+		heartbeat: heartbeat,
+		ring:      make([]event, 0, size),
+		lastID:    uint64(time.Now().UnixMicro()),
+		subs:      map[*eventSub]struct{}{},
+		shutdown:  make(chan struct{}),
+	}
+}
+
+// eventsConfig lee --events-buffer y --events-heartbeat.
+func eventsConfig(opts docopt.Opts) (*eventBroker, error) {
+	sizeStr, _ := opts.String("--events-buffer")
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil || size < 1 {
+		return nil, fmt.Errorf("events: invalid --events-buffer %q", sizeStr)
+	}
+	heartbeatStr, _ := opts.String("--events-heartbeat")
+	heartbeat, err := time.ParseDuration(heartbeatStr)
+	if err != nil || heartbeat <= 0 {
+		return nil, fmt.Errorf("events: invalid --events-heartbeat %q", heartbeatStr)
+	}
+	return newEventBroker(size, heartbeat), nil
+}
+
+// Shutdown termina los streams abiertos, sin esto http.Server.Shutdown esperaria a que los
+// clientes se desconecten.
+func (b *eventBroker) Shutdown() {
+	b.closeOnce.Do(func() { close(b.shutdown) })
+}
+
+// publish asigna el id al evento, lo guarda en el buffer y lo envia a los clientes. No bloquea: un
+// cliente con el buffer lleno se desconecta.
+func (b *eventBroker) publish(ev event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	ev.ID = b.lastID
+	if len(b.ring) < cap(b.ring) {
+		b.ring = append(b.ring, ev)
+	} else {
+		b.ring[b.next] = ev
+		b.next = (b.next + 1) % len(b.ring)
+	}
+	for sub := range b.subs {
+		if !sub.filter.match(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// subscribe registra un cliente y retorna los eventos del buffer posteriores a after, en el mismo
+// lock para que no se pierda ni se repita ningun evento entre el buffer y el canal. Un after mayor
+// al ultimo id no lo asigno este proceso, el cliente recibe el buffer completo.
+func (b *eventBroker) subscribe(filter eventFilter, after uint64) (*eventSub, []event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if after > b.lastID {
+		after = 0
+	}
+	var backlog []event
+	for i := range b.ring {
+		ev := b.ring[(b.next+i)%len(b.ring)]
+		if ev.ID > after && filter.match(ev) {
+			backlog = append(backlog, ev)
+		}
+	}
+	sub := &eventSub{ch: make(chan event, eventSubBuffer), filter: filter}
+	b.subs[sub] = struct{}{}
+	return sub, backlog
+}
+
+func (b *eventBroker) unsubscribe(sub *eventSub) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// publishEvent lo llama el access log al terminar cada request. Las conversiones exitosas y todos
+// los errores son eventos, el resto de los requests no.
+func (h *HTTP) publishEvent(r *http.Request, status int, start time.Time) {
+	if h.events == nil {
+		return
+	}
+	ev := event{
+		Time:     start.UTC(),
+		Method:   r.Method,
+		Route:    r.Pattern,
+		Status:   status,
+		Duration: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if info := infoFrom(r.Context()); info != nil {
+		ev.RequestID = info.id
+		ev.Code = info.code
+	}
+	switch {
+	case status >= 400:
+		ev.Type = eventError
+	case h.conversionRoutes[r.Pattern]:
+		ev.Type = eventConversion
+	default:
+		return
+	}
+	h.events.publish(ev)
+}
+
+// streamEvents es GET /events/stream, envia los eventos por Server-Sent Events. Los parametros route
+// y type, que se pueden repetir, filtran los eventos. Con Last-Event-ID primero se envian los
+// eventos posteriores que sigan en el buffer.
+func (h *HTTP) streamEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := eventFilter{routes: map[string]bool{}, types: map[string]bool{}}
+	for _, route := range query["route"] {
+		filter.routes[route] = true
+	}
+	for _, typ := range query["type"] {
+		if typ != eventConversion && typ != eventError {
+			h.writeProblem(w, r, http.StatusBadRequest, CodeInvalidRequest,
+				fmt.Sprintf("type must be %s or %s", eventConversion, eventError))
+			return
+		}
+		filter.types[typ] = true
+	}
+	var after uint64
+	if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+		id, err := strconv.ParseUint(lastID, 10, 64)
+		if err != nil {
+			h.writeProblem(w, r, http.StatusBadRequest, CodeInvalidRequest, "Last-Event-ID must be a number")
+			return
+		}
+		after = id
+	}
+
+	sub, backlog := h.events.subscribe(filter, after)
+	defer h.events.unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// los proxies como nginx no deben acumular el stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// write pone un deadline a cada escritura, el WriteTimeout del servidor cortaria el stream
+	write := func(text string) bool {
+		rc.SetWriteDeadline(time.Now().Add(eventWriteTimeout))
+		if _, err := fmt.Fprint(w, text); err != nil {
+			return false
+		}
+		return rc.Flush() == nil
+	}
+	frames := fmt.Sprintf("retry: %d\n\n", eventRetry)
+	for _, ev := range backlog {
+		frames += eventFrame(ev)
+	}
+	if !write(frames) {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.events.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case ev, ok := <-sub.ch:
+			if !ok {
+				// el cliente no leia los eventos a tiempo
+				return
+			}
+			if !write(eventFrame(ev)) {
+				return
+			}
+		case <-heartbeat.C:
+			if !write(": heartbeat\n\n") {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-h.events.shutdown:
+			return
+		}
+	}
+}
+
+func eventFrame(ev event) string {
+	data, _ := json.Marshal(ev)
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data)
+}
@@ -0,0 +1,248 @@
+package http_adapter
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+// sseReader lee los frames de un stream de Server-Sent Events.
+type sseReader struct {
+	t       *testing.T
+	scanner *bufio.Scanner
+}
+
+// next retorna el siguiente frame como un mapa campo valor, los comentarios van en el campo vacio.
+func (s *sseReader) next() map[string]string {
+	s.t.Helper()
+	frame := map[string]string{}
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			if len(frame) > 0 {
+				return frame
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		frame[field] = strings.TrimPrefix(value, " ")
+	}
+	s.t.Fatalf("Stream ended: %v", s.scanner.Err())
+	return nil
+}
+
+func newEventsTestServer(t *testing.T, events *eventBroker) *httptest.Server {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.events = events
+	h.SetRoutes()
+	h.SetMiddlewares()
+	server := httptest.NewServer(h.Handler())
+	t.Cleanup(server.Close)
+	t.Cleanup(events.Shutdown)
+	return server
+}
+
+func openStream(t *testing.T, server *httptest.Server, query string, lastID string) *sseReader {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/events/stream"+query, nil)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	reader := &sseReader{t: t, scanner: bufio.NewScanner(resp.Body)}
+	if frame := reader.next(); frame["retry"] == "" {
+		t.Errorf("Expected the retry interval first, got %v", frame)
+	}
+	return reader
+}
+
+func TestEventsStream(t *testing.T) {
+	events := newEventBroker(10, time.Minute)
+	base := events.lastID
+	server := newEventsTestServer(t, events)
+	for _, path := range []string{"/v1/patente/1", "/healthcheck", "/v1/id/nope"} {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		lastID   string
+		expected []event
+	}{
+		{
+			"replays the buffer after Last-Event-ID",
+			"", "0",
+			[]event{
+				{ID: base + 1, Type: eventConversion, Method: "GET", Route: "GET /v1/patente/{id}", Status: 200},
+				{ID: base + 2, Type: eventError, Method: "GET", Route: "GET /v1/id/{patente}", Status: 400, Code: "bad_format"},
+			},
+		},
+		{
+			"filter by type",
+			"?type=error", "0",
+			[]event{
+				{ID: base + 2, Type: eventError, Method: "GET", Route: "GET /v1/id/{patente}", Status: 400, Code: "bad_format"},
+			},
+		},
+		{
+			"filter by route path",
+			"?route=/v1/patente/{id}", "0",
+			[]event{
+				{ID: base + 1, Type: eventConversion, Method: "GET", Route: "GET /v1/patente/{id}", Status: 200},
+			},
+		},
+		{
+			"only events after the last id",
+			"", strconv.FormatUint(base+1, 10),
+			[]event{
+				{ID: base + 2, Type: eventError, Method: "GET", Route: "GET /v1/id/{patente}", Status: 400, Code: "bad_format"},
+			},
+		},
+		{
+			"unknown last id replays the buffer",
+			"", strconv.FormatUint(base+100, 10),
+			[]event{
+				{ID: base + 1, Type: eventConversion, Method: "GET", Route: "GET /v1/patente/{id}", Status: 200},
+				{ID: base + 2, Type: eventError, Method: "GET", Route: "GET /v1/id/{patente}", Status: 400, Code: "bad_format"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := openStream(t, server, tt.query, tt.lastID)
+			var got []event
+			for range tt.expected {
+				frame := stream.next()
+				var ev event
+				if err := json.Unmarshal([]byte(frame["data"]), &ev); err != nil {
+					t.Fatalf("Invalid event %v: %v", frame, err)
+				}
+				if frame["event"] != ev.Type || frame["id"] == "" {
+					t.Errorf("Expected id and event fields, got %v", frame)
+				}
+				if ev.RequestID == "" {
+					t.Errorf("Expected the request id in %v", frame)
+				}
+				ev.Time, ev.Duration, ev.RequestID = time.Time{}, 0, ""
+				got = append(got, ev)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEventsLive(t *testing.T) {
+	server := newEventsTestServer(t, newEventBroker(10, 20*time.Millisecond))
+	stream := openStream(t, server, "?type=conversion", "")
+
+	if frame := stream.next(); frame[""] != "heartbeat" {
+		t.Errorf("Expected a heartbeat, got %v", frame)
+	}
+	resp, err := http.Get(server.URL + "/v1/id/AAAA000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	for {
+		frame := stream.next()
+		if frame[""] == "heartbeat" {
+			continue
+		}
+		if frame["event"] != eventConversion || !strings.Contains(frame["data"], `"route":"GET /v1/id/{patente}"`) {
+			t.Errorf("Expected the conversion event, got %v", frame)
+		}
+		break
+	}
+}
+
+func TestEventBrokerRing(t *testing.T) {
+	broker := newEventBroker(2, time.Minute)
+	base := broker.lastID
+	for i := 0; i < 3; i++ {
+		broker.publish(event{Type: eventConversion})
+	}
+	_, backlog := broker.subscribe(eventFilter{}, 0)
+	if len(backlog) != 2 || backlog[0].ID != base+2 || backlog[1].ID != base+3 {
+		t.Errorf("Expected the last two events, got %+v", backlog)
+	}
+
+	// un cliente que no lee se desconecta en vez de bloquear a los demas
+	sub, _ := broker.subscribe(eventFilter{}, base+3)
+	for i := 0; i < eventSubBuffer+1; i++ {
+		broker.publish(event{Type: eventError})
+	}
+	for range sub.ch {
+	}
+	if _, ok := broker.subs[sub]; ok {
+		t.Error("Expected the slow subscriber to be removed")
+	}
+}
+
+func TestEventBrokerRestart(t *testing.T) {
+	before := newEventBroker(10, time.Minute)
+	before.publish(event{Type: eventConversion})
+	_, old := before.subscribe(eventFilter{}, 0)
+
+	// el proceso nuevo asigna ids mayores, el cliente que reconecta con el id anterior no los salta
+	time.Sleep(time.Millisecond)
+	after := newEventBroker(10, time.Minute)
+	after.publish(event{Type: eventConversion})
+	_, backlog := after.subscribe(eventFilter{}, old[0].ID)
+	if len(backlog) != 1 || backlog[0].ID <= old[0].ID {
+		t.Errorf("Expected the new event after %d, got %+v", old[0].ID, backlog)
+	}
+}
+
+func TestEventsBadRequest(t *testing.T) {
+	server := newEventsTestServer(t, newEventBroker(10, time.Minute))
+	tests := []struct {
+		name   string
+		query  string
+		lastID string
+	}{
+		{"unknown type", "?type=nope", ""},
+		{"invalid Last-Event-ID", "", "abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/events/stream"+tt.query, nil)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
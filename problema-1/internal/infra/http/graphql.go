@@ -0,0 +1,326 @@
+package http_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/graphql"
+	"github.com/docopt/docopt-go"
+)
+
+const (
+	// graphqlMaxRange y graphqlMaxNeighbours acotan las listas, la complejidad de la query ya las
+	// limita pero asi un solo campo no puede generar una respuesta enorme
+	graphqlMaxRange      = 100
+	graphqlMaxNeighbours = 10
+)
+
+// plateNode es el valor de los objetos Plate del schema.
+type plateNode struct {
+	id      uint
+	patente string
+}
+
+// validation es el valor de los objetos Validation del schema.
+type validation struct {
+	input   string
+	code    string
+	message string
+	plate   *plateNode
+}
+
+// graphqlLimits lee --graphql-max-depth y --graphql-max-complexity.
+func graphqlLimits(opts docopt.Opts) (graphql.Limits, error) {
+	depthStr, _ := opts.String("--graphql-max-depth")
+	depth, err := strconv.Atoi(depthStr)
+	if err != nil || depth < 0 {
+		return graphql.Limits{}, fmt.Errorf("graphql: invalid --graphql-max-depth %q", depthStr)
+	}
+	complexityStr, _ := opts.String("--graphql-max-complexity")
+	complexity, err := strconv.Atoi(complexityStr)
+	if err != nil || complexity < 0 {
+		return graphql.Limits{}, fmt.Errorf("graphql: invalid --graphql-max-complexity %q", complexityStr)
+	}
+	return graphql.Limits{MaxDepth: depth, MaxComplexity: complexity}, nil
+}
+
+// buildGraphQLSchema arma el schema sobre las conversiones de app.
+func (h *HTTP) buildGraphQLSchema() *graphql.Schema {
+	plate := &graphql.Object{Name: "Plate", Description: "Una patente y su id"}
+	plate.Fields = []*graphql.Field{
+		{
+			Name: "id",
+			Type: &graphql.NonNull{Of: graphql.Int},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(*plateNode).id, nil
+			},
+		},
+		{
+			Name: "plate",
+			Type: &graphql.NonNull{Of: graphql.String},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(*plateNode).patente, nil
+			},
+		},
+		{
+			Name:        "previous",
+			Description: "La patente anterior, null para la primera",
+			Type:        plate,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id := source.(*plateNode).id
+				if id <= 1 {
+					return nil, nil
+				}
+				return h.plateNode(ctx, id-1)
+			},
+		},
+		{
+			Name:        "next",
+			Description: "La patente siguiente, null para la ultima",
+			Type:        plate,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id := source.(*plateNode).id
+				if id >= app.MaxID {
+					return nil, nil
+				}
+				return h.plateNode(ctx, id+1)
+			},
+		},
+		{
+			Name:        "neighbours",
+			Description: fmt.Sprintf("Las count patentes anteriores y siguientes, count hasta %d", graphqlMaxNeighbours),
+			Args:        []*graphql.Arg{{Name: "count", Type: &graphql.NonNull{Of: graphql.Int}, Default: 1}},
+			Type:        &graphql.NonNull{Of: &graphql.List{Of: &graphql.NonNull{Of: plate}}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				count := args["count"].(int)
+				if count < 0 || count > graphqlMaxNeighbours {
+					return nil, invalidArgument(fmt.Sprintf("count must be between 0 and %d", graphqlMaxNeighbours))
+				}
+				id := int(source.(*plateNode).id)
+				var plates []*plateNode
+				for n := max(1, id-count); n <= min(app.MaxID, id+count); n++ {
+					if n == id {
+						continue
+					}
+					node, err := h.plateNode(ctx, uint(n))
+					if err != nil {
+						return nil, err
+					}
+					plates = append(plates, node)
+				}
+				return plates, nil
+			},
+			Multiplier: func(args map[string]any) int {
+				return 2 * max(0, min(args["count"].(int), graphqlMaxNeighbours))
+			},
+		},
+	}
+
+	result := &graphql.Object{Name: "Validation", Description: "Resultado de validar una patente"}
+	result.Fields = []*graphql.Field{
+		{
+			Name:        "input",
+			Description: "La patente recibida",
+			Type:        &graphql.NonNull{Of: graphql.String},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(*validation).input, nil
+			},
+		},
+		{
+			Name: "valid",
+			Type: &graphql.NonNull{Of: graphql.Boolean},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(*validation).plate != nil, nil
+			},
+		},
+		{
+			Name:        "code",
+			Description: "Codigo del error si no es valida",
+			Type:        graphql.String,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				if code := source.(*validation).code; code != "" {
+					return code, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name:        "message",
+			Description: "Mensaje del error si no es valida",
+			Type:        graphql.String,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				if message := source.(*validation).message; message != "" {
+					return message, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name:        "plate",
+			Description: "La patente normalizada si es valida",
+			Type:        plate,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(*validation).plate, nil
+			},
+		},
+	}
+
+	query := &graphql.Object{Name: "Query", Fields: []*graphql.Field{
+		{
+			Name:        "plate",
+			Description: "La patente del id",
+			Args:        []*graphql.Arg{{Name: "id", Type: &graphql.NonNull{Of: graphql.Int}}},
+			Type:        plate,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				// los negativos se convierten a 0 para que app los rechace como fuera de rango
+				return h.plateNode(ctx, uint(max(args["id"].(int), 0)))
+			},
+		},
+		{
+			Name:        "id",
+			Description: "El id de la patente",
+			Args:        []*graphql.Arg{{Name: "plate", Type: &graphql.NonNull{Of: graphql.String}}},
+			Type:        graphql.Int,
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				id, err := h.app.IDFromPatent(ctx, args["plate"].(string))
+				if err != nil {
+					return nil, h.graphqlError(ctx, err)
+				}
+				return id, nil
+			},
+		},
+		{
+			Name:        "range",
+			Description: fmt.Sprintf("Las count patentes consecutivas desde el id from, count hasta %d", graphqlMaxRange),
+			Args: []*graphql.Arg{
+				{Name: "from", Type: &graphql.NonNull{Of: graphql.Int}},
+				{Name: "count", Type: &graphql.NonNull{Of: graphql.Int}, Default: 10},
+			},
+			Type: &graphql.List{Of: &graphql.NonNull{Of: plate}},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				from, count := args["from"].(int), args["count"].(int)
+				if count < 0 || count > graphqlMaxRange {
+					return nil, invalidArgument(fmt.Sprintf("count must be between 0 and %d", graphqlMaxRange))
+				}
+				plates := []*plateNode{}
+				if count == 0 {
+					return plates, nil
+				}
+				first, err := h.plateNode(ctx, uint(max(from, 0)))
+				if err != nil {
+					return nil, err
+				}
+				plates = append(plates, first)
+				for id := from + 1; id < from+count && id <= app.MaxID; id++ {
+					node, err := h.plateNode(ctx, uint(id))
+					if err != nil {
+						return nil, err
+					}
+					plates = append(plates, node)
+				}
+				return plates, nil
+			},
+			Multiplier: func(args map[string]any) int {
+				return max(0, min(args["count"].(int), graphqlMaxRange))
+			},
+		},
+		{
+			Name:        "validate",
+			Description: "Valida una patente, los errores de formato se informan en el resultado",
+			Args:        []*graphql.Arg{{Name: "plate", Type: &graphql.NonNull{Of: graphql.String}}},
+			Type:        &graphql.NonNull{Of: result},
+			Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				input := args["plate"].(string)
+				id, err := h.app.IDFromPatent(ctx, input)
+				if code := app.ErrorCode(err); code != "" {
+					return &validation{input: input, code: code, message: appMessage(ctx, err)}, nil
+				}
+				if err != nil {
+					return nil, h.graphqlError(ctx, err)
+				}
+				node, err := h.plateNode(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				return &validation{input: input, plate: node}, nil
+			},
+		},
+	}}
+	return &graphql.Schema{Query: query}
+}
+
+func (h *HTTP) plateNode(ctx context.Context, id uint) (*plateNode, error) {
+	patente, err := h.app.PatentFromID(ctx, id)
+	if err != nil {
+		return nil, h.graphqlError(ctx, err)
+	}
+	return &plateNode{id: id, patente: patente}, nil
+}
+
+// graphqlError pone el codigo del error de dominio en las extensions, igual que writeAppError un
+// error sin codigo es interno y su texto no se expone.
+func (h *HTTP) graphqlError(ctx context.Context, err error) error {
+	code := app.ErrorCode(err)
+	if code == "" {
+		h.logger.ErrorContext(ctx, "Unexpected app error", "error", err.Error())
+		return &graphql.Error{Message: "internal error", Extensions: map[string]any{"code": CodeInternal}}
+	}
+	return &graphql.Error{Message: appMessage(ctx, err), Extensions: map[string]any{"code": code}}
+}
+
+func invalidArgument(message string) error {
+	return &graphql.Error{Message: message, Extensions: map[string]any{"code": CodeInvalidArgument}}
+}
+
+// graphql atiende GET /graphql?query=... y POST /graphql con un body JSON o application/graphql. Las
+// queries invalidas o que exceden los limites responden 400 sin ejecutarse, los errores de los
+// campos responden 200 con data parcial.
+func (h *HTTP) graphql(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if vars := query.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				h.writeProblem(w, r, http.StatusBadRequest, CodeInvalidRequest, "variables must be a JSON object")
+				return
+			}
+		}
+	} else {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.writeAppError(w, r, err)
+			return
+		}
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/graphql" {
+			req.Query = string(body)
+		} else if err := json.Unmarshal(body, &req); err != nil {
+			h.writeProblem(w, r, http.StatusBadRequest, CodeInvalidRequest, "body must be a JSON object with a query")
+			return
+		}
+	}
+	if req.Query == "" {
+		h.writeProblem(w, r, http.StatusBadRequest, CodeInvalidRequest, "query is required")
+		return
+	}
+
+	resp := h.graphqlSchema.Execute(r.Context(), req, h.graphqlLimits)
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// graphqlSDL sirve el schema en SDL para generar clientes.
+func (h *HTTP) graphqlSDL(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, h.graphqlSchema.SDL())
+}
@@ -0,0 +1,151 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/graphql"
+)
+
+func TestGraphQL(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.graphqlLimits = graphql.Limits{MaxDepth: 4, MaxComplexity: 50}
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		contentType  string
+		body         string
+		expectedCode int
+		expected     string
+	}{
+		{
+			"plate with neighbours in one round trip",
+			http.MethodPost, "/graphql", "application/json",
+			`{"query": "{ plate(id: 2) { id plate neighbours { plate } } id(plate: \"AAAA002\") }"}`,
+			http.StatusOK,
+			`{"data": {"plate": {"id": 2, "plate": "AAAA001", "neighbours": [{"plate": "AAAA000"}, {"plate": "AAAA002"}]}, "id": 3}}`,
+		},
+		{
+			"range and validation",
+			http.MethodPost, "/graphql", "application/json",
+			`{"query": "query($p: String!) { range(from: 1, count: 2) { plate } ok: validate(plate: $p) { valid plate { id } } bad: validate(plate: \"AA\") { valid code } }", "variables": {"p": "aaaa000"}}`,
+			http.StatusOK,
+			`{"data": {"range": [{"plate": "AAAA000"}, {"plate": "AAAA001"}], "ok": {"valid": true, "plate": {"id": 1}}, "bad": {"valid": false, "code": "bad_format"}}}`,
+		},
+		{
+			"first plate has no previous",
+			http.MethodGet, "/graphql?query=" + url.QueryEscape(`query($id: Int!) { plate(id: $id) { previous { id } next { id } } }`) + "&variables=" + url.QueryEscape(`{"id": 1}`), "", "",
+			http.StatusOK,
+			`{"data": {"plate": {"previous": null, "next": {"id": 2}}}}`,
+		},
+		{
+			"application/graphql body",
+			http.MethodPost, "/graphql", "application/graphql",
+			`{ plate(id: 1) { plate } }`,
+			http.StatusOK,
+			`{"data": {"plate": {"plate": "AAAA000"}}}`,
+		},
+		{
+			"domain error in extensions",
+			http.MethodPost, "/graphql", "application/json",
+			`{"query": "{ plate(id: 0) { plate } }"}`,
+			http.StatusOK,
+			`{"errors": [{"message": "the id is out of range, valid ids go from 1 to 456976000", "locations": [{"line": 1, "column": 3}], "path": ["plate"], "extensions": {"code": "invalid_range"}}], "data": {"plate": null}}`,
+		},
+		{
+			"too deep",
+			http.MethodPost, "/graphql", "application/json",
+			`{"query": "{ plate(id: 1) { next { next { next { next { id } } } } } }"}`,
+			http.StatusBadRequest,
+			`{"errors": [{"message": "Query depth exceeds the limit of 4.", "locations": [{"line": 1, "column": 39}]}]}`,
+		},
+		{
+			"too complex",
+			http.MethodPost, "/graphql", "application/json",
+			`{"query": "{ range(from: 1, count: 30) { plate neighbours { id } } }"}`,
+			http.StatusBadRequest,
+			`{"errors": [{"message": "Query complexity exceeds the limit of 50.", "locations": [{"line": 1, "column": 3}]}]}`,
+		},
+		{
+			"negative count on an alias does not offset the complexity",
+			http.MethodPost, "/graphql", "application/json",
+			`{"query": "{ a: range(from: 1, count: -100000) { id } b: range(from: 1, count: 30) { plate neighbours { id } } }"}`,
+			http.StatusBadRequest,
+			`{"errors": [{"message": "Query complexity exceeds the limit of 50.", "locations": [{"line": 1, "column": 3}]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			var got, expected any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			json.Unmarshal([]byte(tt.expected), &expected)
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestGraphQLBadRequests(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"body is not json", http.MethodPost, "/graphql", `query { plate(id: 1) { id } }`},
+		{"missing query", http.MethodPost, "/graphql", `{"variables": {}}`},
+		{"invalid variables", http.MethodGet, "/graphql?query=%7Bid%7D&variables=nope", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var body map[string]any
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body["code"] != CodeInvalidRequest {
+				t.Errorf("Expected code %q, got %v", CodeInvalidRequest, body["code"])
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/graphql/schema", nil))
+	if !strings.Contains(rec.Body.String(), "neighbours(count: Int! = 1): [Plate!]!") {
+		t.Errorf("Expected the SDL, got %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,82 @@
+package http_adapter
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	grpc_adapter "github.com/do-prueba-tecnica/problema-1/internal/infra/grpc"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/grpc/patentespb"
+)
+
+// grpcRoutes son las rutas http equivalentes a cada rpc, la politica JWT se evalua sobre ellas.
+// ConvertBatch convierte en los dos sentidos y exige ambas.
+var grpcRoutes = map[string][]string{
+	patentespb.Converter_PatentFromID_FullMethodName: {"GET /v1/patente/{id}"},
+	patentespb.Converter_IDFromPatent_FullMethodName: {"GET /v1/id/{patente}"},
+	patentespb.Converter_ConvertBatch_FullMethodName: {"GET /v1/patente/{id}", "GET /v1/id/{patente}"},
+}
+
+// grpcAuthorize aplica a las rpc de conversion las mismas credenciales y la misma autorizacion que a
+// las rutas http equivalentes, nil si la api no exige autenticacion. Como en authenticate, las
+// credenciales invalidas se cuentan por IP en authFailures.
+func (h *HTTP) grpcAuthorize() func(method string, ip string, token string, cert *x509.Certificate) (string, error) {
+	if !h.authEnabled() {
+		return nil
+	}
+	return func(method string, ip string, token string, cert *x509.Certificate) (string, error) {
+		var principal *Principal
+		if cert != nil {
+			principal = certPrincipal(cert)
+		}
+		if token != "" {
+			key := "ip:" + ip
+			if h.authFailures != nil {
+				if wait, blocked := h.authFailures.Blocked(key, time.Now()); blocked {
+					return "", &grpc_adapter.LimitError{Code: CodeRateLimited, Message: "too many failed authentications", RetryAfter: wait}
+				}
+			}
+			var detail string
+			principal, detail = h.principalFromToken(context.Background(), token)
+			if principal == nil {
+				if h.authFailures != nil {
+					h.authFailures.Allow(key, time.Now())
+				}
+				return "", errors.New(detail)
+			}
+		}
+		if principal == nil {
+			return "", errors.New("credentials are required")
+		}
+		routes, ok := grpcRoutes[method]
+		if !ok {
+			return "", fmt.Errorf("%w: unknown method %s", grpc_adapter.ErrPermissionDenied, method)
+		}
+		for _, pattern := range routes {
+			if detail := h.denied(principal, pattern, ScopeConvertRead); detail != "" {
+				return "", fmt.Errorf("%w: %s", grpc_adapter.ErrPermissionDenied, detail)
+			}
+		}
+		return principal.key(), nil
+	}
+}
+
+// grpcLimit cobra cada conversion de gRPC en el mismo limiter y cuota que la api http, nil si no hay
+// limites.
+func (h *HTTP) grpcLimit() func(key string) error {
+	if h.limiter == nil && h.quota == nil {
+		return nil
+	}
+	return func(key string) error {
+		code, retryAfter := h.charge(key, time.Now())
+		switch code {
+		case CodeRateLimited:
+			return &grpc_adapter.LimitError{Code: code, Message: "too many requests", RetryAfter: retryAfter}
+		case CodeQuotaExceeded:
+			return &grpc_adapter.LimitError{Code: code, Message: "daily quota exceeded", RetryAfter: retryAfter}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package http_adapter
+
+import (
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	grpc_adapter "github.com/do-prueba-tecnica/problema-1/internal/infra/grpc"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/grpc/patentespb"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/jwtauth"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
+)
+
+func TestGRPCAuthorize(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	if h.grpcAuthorize() != nil {
+		t.Fatalf("Expected no authorize hook without authentication")
+	}
+
+	keys, err := apikey.Open(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reader, _, err := keys.Create("lector", []string{ScopeConvertRead}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	other, _, err := keys.Create("otro", []string{"other:read"}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h.keys = keys
+	h.mtls = true
+	authorize := h.grpcAuthorize()
+
+	_, clientCert := newTestCA(t, "kiosko-1", []string{ScopeConvertRead})
+	cert, err := x509.ParseCertificate(clientCert.Certificate[0])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		cert           *x509.Certificate
+		expectedErr    bool
+		expectedDenied bool
+	}{
+		{"reader key", reader, nil, false, false},
+		{"client certificate", "", cert, false, false},
+		{"anonymous", "", nil, true, false},
+		{"invalid key", "pk_nope_nope", nil, true, false},
+		{"missing scope", other, nil, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := authorize(patentespb.Converter_PatentFromID_FullMethodName, "192.0.2.1", tt.token, tt.cert)
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if denied := errors.Is(err, grpc_adapter.ErrPermissionDenied); denied != tt.expectedDenied {
+				t.Errorf("Expected permission denied %v, got %v", tt.expectedDenied, err)
+			}
+			if err == nil && key == "" {
+				t.Errorf("Expected a client key for the limits")
+			}
+		})
+	}
+}
+
+// con politica las rpc se autorizan como las rutas http equivalentes, un token con el scope que la
+// politica no permite se rechaza igual que en http
+func TestGRPCAuthorizePolicy(t *testing.T) {
+	secret := []byte("01234567890123456789012345678901")
+	key, err := jwtauth.NewHMACKey("", secret)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verifier, err := jwtauth.NewVerifier(jwtauth.Options{Keys: []jwtauth.Key{key}, Audience: "patentes"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := newTestHTTP(&strings.Builder{})
+	h.jwt = verifier
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	policyContent := `{"rules": [
+		{"routes": ["GET /v1/patente/{id}"], "claims": {"groups": "kiosko"}},
+		{"routes": ["*"], "claims": {"groups": "ops"}}
+	]}`
+	if err := os.WriteFile(policyPath, []byte(policyContent), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if h.policy, err = jwtauth.LoadPolicy(policyPath); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	authorize := h.grpcAuthorize()
+
+	exp := time.Now().Add(time.Hour).Unix()
+	kiosko := signHS256(secret, map[string]any{"sub": "k1", "aud": "patentes", "exp": exp, "groups": []string{"kiosko"}})
+	ops := signHS256(secret, map[string]any{"sub": "o1", "aud": "patentes", "exp": exp, "groups": []string{"ops"}})
+	withScope := signHS256(secret, map[string]any{"sub": "s1", "aud": "patentes", "exp": exp, "scope": ScopeConvertRead})
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		expectedDenied bool
+	}{
+		{"policy allows the route", patentespb.Converter_PatentFromID_FullMethodName, kiosko, false},
+		{"policy denies the route", patentespb.Converter_IDFromPatent_FullMethodName, kiosko, true},
+		{"batch needs both routes", patentespb.Converter_ConvertBatch_FullMethodName, kiosko, true},
+		{"policy wildcard", patentespb.Converter_ConvertBatch_FullMethodName, ops, false},
+		{"scope without a policy rule", patentespb.Converter_PatentFromID_FullMethodName, withScope, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := authorize(tt.method, "192.0.2.1", tt.token, nil)
+			if denied := errors.Is(err, grpc_adapter.ErrPermissionDenied); denied != tt.expectedDenied {
+				t.Errorf("Expected permission denied %v, got %v", tt.expectedDenied, err)
+			}
+		})
+	}
+}
+
+func TestGRPCAuthFailures(t *testing.T) {
+	keys, err := apikey.Open(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reader, _, err := keys.Create("lector", []string{ScopeConvertRead}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := newTestHTTP(&strings.Builder{})
+	h.keys = keys
+	h.authFailures = ratelimit.NewLimiter(0.001, 3)
+	authorize := h.grpcAuthorize()
+	method := patentespb.Converter_PatentFromID_FullMethodName
+
+	for i := range 3 {
+		if _, err := authorize(method, "192.0.2.1", "pk_nope_nope", nil); err == nil || errors.As(err, new(*grpc_adapter.LimitError)) {
+			t.Fatalf("Expected attempt %d to be unauthenticated, got %v", i, err)
+		}
+	}
+	// agotados los intentos ni una key valida se revisa
+	for _, key := range []string{"pk_nope_nope", reader} {
+		_, err := authorize(method, "192.0.2.1", key, nil)
+		var limitErr *grpc_adapter.LimitError
+		if !errors.As(err, &limitErr) || limitErr.Code != CodeRateLimited || limitErr.RetryAfter <= 0 {
+			t.Fatalf("Expected a rate limited error with retry delay, got %v", err)
+		}
+	}
+	if _, err := authorize(method, "192.0.2.2", reader, nil); err != nil {
+		t.Errorf("Expected other IPs unaffected, got %v", err)
+	}
+}
+
+func TestGRPCLimit(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	if h.grpcLimit() != nil {
+		t.Fatalf("Expected no limit hook without limits")
+	}
+
+	h.limiter = ratelimit.NewLimiter(1, 2)
+	limit := h.grpcLimit()
+	for i := range 2 {
+		if err := limit("api_key:k1"); err != nil {
+			t.Fatalf("Expected conversion %d allowed, got %v", i, err)
+		}
+	}
+	var limitErr *grpc_adapter.LimitError
+	if err := limit("api_key:k1"); !errors.As(err, &limitErr) || limitErr.Code != CodeRateLimited || limitErr.RetryAfter <= 0 {
+		t.Errorf("Expected a rate limit error, got %v", err)
+	}
+	// el limite es por cliente
+	if err := limit("api_key:k2"); err != nil {
+		t.Errorf("Expected another client allowed, got %v", err)
+	}
+}
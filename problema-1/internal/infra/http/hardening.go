@@ -0,0 +1,118 @@
+package http_adapter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docopt/docopt-go"
+)
+
+const CodeBodyTooLarge = "body_too_large"
+
+// serverLimits son los timeouts y limites del http.Server, sin ellos un cliente lento (slowloris)
+// puede mantener conexiones abiertas indefinidamente.
+type serverLimits struct {
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	maxHeaderBytes    int
+}
+
+func parseServerLimits(opts docopt.Opts) (serverLimits, error) {
+	var limits serverLimits
+	durations := []struct {
+		option string
+		value  *time.Duration
+	}{
+		{"--read-header-timeout", &limits.readHeaderTimeout},
+		{"--read-timeout", &limits.readTimeout},
+		{"--write-timeout", &limits.writeTimeout},
+		{"--idle-timeout", &limits.idleTimeout},
+	}
+	for _, d := range durations {
+		value, _ := opts.String(d.option)
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			return serverLimits{}, fmt.Errorf("server: %s must be a positive duration, got %q", d.option, value)
+		}
+		*d.value = parsed
+	}
+
+	value, _ := opts.String("--max-header-bytes")
+	maxHeaderBytes, err := strconv.Atoi(value)
+	if err != nil || maxHeaderBytes <= 0 {
+		return serverLimits{}, fmt.Errorf("server: --max-header-bytes must be a positive number, got %q", value)
+	}
+	limits.maxHeaderBytes = maxHeaderBytes
+	return limits, nil
+}
+
+func (l serverLimits) apply(server *http.Server) {
+	server.ReadHeaderTimeout = l.readHeaderTimeout
+	server.ReadTimeout = l.readTimeout
+	server.WriteTimeout = l.writeTimeout
+	server.IdleTimeout = l.idleTimeout
+	server.MaxHeaderBytes = l.maxHeaderBytes
+}
+
+// parseBodyLimits lee una lista "PATRON=BYTES,..." con el tamaño maximo del body por ruta, por
+// ejemplo "POST /rpc=65536". Los patrones son los mismos registrados en SetRoutes.
+func parseBodyLimits(value string) (map[string]int64, error) {
+	limits := map[string]int64{}
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		pattern, bytesStr, found := strings.Cut(item, "=")
+		if !found {
+			return nil, fmt.Errorf("body limit: %q must have the form PATTERN=BYTES", item)
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(bytesStr), 10, 64)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("body limit: limit for %q must be a number of bytes", pattern)
+		}
+		limits[strings.TrimSpace(pattern)] = limit
+	}
+	return limits, nil
+}
+
+// limitBody corta los bodies mas grandes que el limite de la ruta, o que maxBody si la ruta no tiene
+// uno propio. Si el Content-Length ya lo excede se responde 413 sin leerlo, si no el handler recibe
+// un *http.MaxBytesError al pasarse.
+func (h *HTTP) limitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := h.maxBody
+		if _, pattern := h.mux.Handler(r); pattern != "" {
+			if routeLimit, ok := h.bodyLimits[pattern]; ok {
+				limit = routeLimit
+			}
+		}
+		if r.ContentLength > limit {
+			h.writeProblem(w, r, http.StatusRequestEntityTooLarge, CodeBodyTooLarge,
+				fmt.Sprintf("request body is limited to %d bytes", limit))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// securityHeaders agrega los headers de seguridad a todas las respuestas. HSTS solo se envia sobre
+// TLS, en texto plano los navegadores lo ignoran.
+func (h *HTTP) securityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		header.Set("Referrer-Policy", "no-referrer")
+		if r.TLS != nil {
+			header.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,163 @@
+package http_adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name string
+		tls  bool
+		hsts string
+	}{
+		{"plain http", false, ""},
+		{"tls", true, "max-age=31536000; includeSubDomains"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			expected := map[string]string{
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "no-referrer",
+				"Strict-Transport-Security": tt.hsts,
+			}
+			for header, value := range expected {
+				if got := rec.Header().Get(header); got != value {
+					t.Errorf("Expected %s %q, got %q", header, value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.maxBody = 8
+	h.bodyLimits = map[string]int64{"POST /big": 64}
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			h.writeAppError(w, r, err)
+			return
+		}
+		io.WriteString(w, "ok")
+	}
+	h.mux.HandleFunc("POST /small", echo)
+	h.mux.HandleFunc("POST /big", echo)
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		chunked      bool
+		expectedCode int
+	}{
+		{"within default", "/small", "12345678", false, http.StatusOK},
+		{"over default", "/small", "123456789", false, http.StatusRequestEntityTooLarge},
+		{"over default chunked", "/small", "123456789", true, http.StatusRequestEntityTooLarge},
+		{"route limit", "/big", strings.Repeat("a", 64), false, http.StatusOK},
+		{"over route limit", "/big", strings.Repeat("a", 65), true, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.chunked {
+				// sin Content-Length el limite se aplica al leer
+				req.ContentLength = -1
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK && !strings.Contains(rec.Body.String(), CodeBodyTooLarge) {
+				t.Errorf("Expected code %s, got %s", CodeBodyTooLarge, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSlowClients(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	baseURL := setupTestServer(t, ctx, "--read-header-timeout=200ms", "--read-timeout=500ms", "--max-header-bytes=1024")
+	addr := strings.TrimPrefix(baseURL, "http://")
+
+	dial := func(t *testing.T) net.Conn {
+		for range 50 {
+			conn, err := net.Dial("tcp", addr)
+			if err == nil {
+				return conn
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("No se pudo conectar a %s", addr)
+		return nil
+	}
+
+	t.Run("slow headers are cut off", func(t *testing.T) {
+		conn := dial(t)
+		defer conn.Close()
+
+		// el cliente envia un header cada 100ms y nunca termina el request
+		start := time.Now()
+		io.WriteString(conn, "GET /healthcheck HTTP/1.1\r\nHost: localhost\r\n")
+		closed := make(chan struct{})
+		go func() {
+			io.Copy(io.Discard, conn)
+			close(closed)
+		}()
+		for i := 0; ; i++ {
+			select {
+			case <-closed:
+				if elapsed := time.Since(start); elapsed > 2*time.Second {
+					t.Errorf("Se esperaba que el servidor cerrara la conexion cerca de 200ms, tardo %v", elapsed)
+				}
+				return
+			case <-time.After(100 * time.Millisecond):
+				if i > 30 {
+					t.Fatal("El servidor no cerro la conexion del cliente lento")
+				}
+				io.WriteString(conn, "X-Slow: 1\r\n")
+			}
+		}
+	})
+
+	t.Run("large headers are rejected", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, baseURL+"/healthcheck", nil)
+		if err != nil {
+			t.Fatalf("Error al crear la solicitud: %v", err)
+		}
+		req.Header.Set("X-Big", strings.Repeat("a", 8192))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Error al hacer la solicitud: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+			t.Errorf("Se esperaba status 431, se obtuvo %d", resp.StatusCode)
+		}
+	})
+}
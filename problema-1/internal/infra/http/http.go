@@ -5,22 +5,79 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 
 	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/graphql"
+	grpc_adapter "github.com/do-prueba-tecnica/problema-1/internal/infra/grpc"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/jwtauth"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
 	"github.com/do-prueba-tecnica/problema-1/pkgs/assertor"
 	"github.com/docopt/docopt-go"
 )
 
+const version = "0.0.1"
+
 type HTTP struct {
-	app    *app.App
+	app    app.ServiceV1
 	stderr io.Writer
 	stdout io.Writer
 	logger *slog.Logger
 	mux    *http.ServeMux
+
+	middlewares []Middleware
+	logSampling map[string]float64
+	metrics     *httpMetrics
+	// metricsAddr es la direccion del listener de administracion, vacia para servir /metrics en el
+	// mismo puerto de la api
+	metricsAddr string
+	// keys es nil si no hay archivo de api keys, en ese caso la api no exige autenticacion
+	keys *apikey.Store
+	// jwt es nil si no hay llaves para verificar JWT, policy es opcional y reemplaza a los scopes
+	jwt    *jwtauth.Verifier
+	policy *jwtauth.Policy
+	// mtls indica que los clientes se autentican con certificado
+	mtls bool
+	// maxBody es el limite del body de las rutas que no tienen uno en bodyLimits
+	maxBody    int64
+	bodyLimits map[string]int64
+	// corsConfig es nil si no se permiten origenes externos
+	corsConfig *corsConfig
+	// cacheMaxAge es el max-age de las conversiones, 0 usa defaultCacheMaxAge
+	cacheMaxAge time.Duration
+	// compressMinSize es el tamaño desde el que se comprimen las respuestas
+	compressMinSize int
+	// routeVersions es la version de cada patron versionado, legacy para los sin version, y
+	// routeAliases une el patron con version y el sin version de las rutas deprecadas
+	routeVersions map[string]string
+	routeAliases  map[string]string
+	// sunset es la fecha en que se dejan de servir las rutas sin version
+	sunset time.Time
+	// ws limita las conexiones de /v1/ws
+	ws *wsHub
+	// events reparte los eventos de /events/stream, conversionRoutes son los patrones cuyos
+	// requests exitosos son eventos de conversion
+	events           *eventBroker
+	conversionRoutes map[string]bool
+	// graphqlSchema lo arma SetRoutes, graphqlLimits en cero no limita las queries
+	graphqlSchema *graphql.Schema
+	graphqlLimits graphql.Limits
+	// started y config los muestra /diagnostics, config sin las opciones con credenciales
+	started time.Time
+	config  map[string]string
+	// limiter y quota son nil si no se configuraron limites, authFailures limita las autenticaciones
+	// fallidas por IP y es nil si se desactivo
+	limiter        *ratelimit.Limiter
+	quota          *ratelimit.Quota
+	authFailures   *ratelimit.Limiter
+	trustedProxies []netip.Prefix
 }
 
 func Run(
@@ -40,20 +97,68 @@ func Run(
 	usage := `sos beacon app http.
 
 Usage:
-    sos_beacon [--format=<j>] [--host=<h>]
+    sos_beacon [options]
+    sos_beacon migrate [--steps=<n>] [--direction=<d>] [--path=<p>] [--dry-run] [--format=<j>]
+    sos_beacon keys create --label=<l> --scopes=<s> [--expires=<d>] [--keys=<f>]
+    sos_beacon keys list [--keys=<f>] [--format=<j>]
+    sos_beacon keys revoke <id> [--keys=<f>]
     sos_beacon -h | --help
     sos_beacon --version
     
 Options:
-    -h --help         Show this screen.
-    --version         Show version.
-    --steps=<n>       Steps to move the migration [default: 0].
-    --direction=<d>   Direction to move the migrations [default: up].
-    --path=<p>        Path with the migrations [default: migrations/].
-    --format=<j>      Format output as json [default: text]
-    --host=<h>        Host to bind [default: 0.0.0.0]`
-
-	const version = "0.0.1"
+    -h --help                     Show this screen.
+    --version                     Show version.
+    --steps=<n>                   Steps to move the migration, 0 moves all and is not allowed down [default: 0].
+    --direction=<d>               Direction to move the migrations [default: up].
+    --path=<p>                    Path with the migrations [default: migrations/].
+    --dry-run                     Show the migrations to run without applying them.
+    --format=<j>                  Format output as json [default: text]
+    --host=<h>                    Host to bind [default: 0.0.0.0]
+    --log-sample=<s>              Fraction of successful requests to log per route, e.g. "GET /healthcheck=0.01".
+    --metrics-addr=<a>            Serve /metrics on a separate admin listener, e.g. 127.0.0.1:9090.
+    --keys=<f>                    API keys file, enables authentication. Defaults to the API_KEYS_FILE env var.
+    --label=<l>                   Label of the new api key.
+    --scopes=<s>                  Comma separated scopes of the new api key, e.g. convert:read,admin.
+    --expires=<d>                 Lifetime of the new api key, e.g. 720h, 0 never expires [default: 0].
+    --jwt-jwks=<f>                JWKS file with the keys to verify JWT bearer tokens.
+    --jwt-key=<f>                 PEM public key (RS256 or ES256) to verify JWT bearer tokens.
+    --jwt-issuer=<i>              Required iss claim of JWT bearer tokens.
+    --jwt-audience=<a>            Required aud claim of JWT bearer tokens.
+    --jwt-policy=<f>              Policy file mapping JWT claims to allowed routes.
+    --rate=<r>                    Requests per second allowed per client, 0 disables rate limiting [default: 0].
+    --burst=<b>                   Requests a client can make at once, defaults to the rate rounded up [default: 0].
+    --quota=<n>                   Requests per day allowed per client, 0 disables quotas [default: 0].
+    --quota-file=<f>              File where the daily quota counters are persisted.
+    --auth-failures=<n>           Failed authentications allowed per client IP per minute before answering 429, 0 disables [default: 10].
+    --trusted-proxies=<c>         Comma separated proxy IPs or CIDRs whose X-Forwarded-For is trusted.
+    --tls-cert=<f>                PEM certificate to serve HTTPS, reloaded when the file changes.
+    --tls-key=<f>                 PEM private key of --tls-cert.
+    --tls-client-ca=<f>           PEM CA bundle, requires clients to present a certificate signed by it.
+    --dev-tls                     Serve HTTPS with an in-memory self-signed certificate for local development.
+    --read-header-timeout=<d>     Time allowed to read the request headers [default: 5s].
+    --read-timeout=<d>            Time allowed to read the whole request [default: 15s].
+    --write-timeout=<d>           Time allowed to write the response [default: 30s].
+    --idle-timeout=<d>            Time a keep-alive connection waits for the next request [default: 120s].
+    --max-header-bytes=<n>        Maximum size of the request headers [default: 16384].
+    --max-body=<n>                Maximum request body size in bytes [default: 1048576].
+    --body-limit=<l>              Body size per route overriding --max-body, e.g. "POST /rpc=65536".
+    --cors-origins=<o>            Comma separated origins allowed to call the api from a browser, e.g. https://*.example.com.
+    --cors-methods=<m>            Methods allowed in CORS requests [default: GET,POST].
+    --cors-headers=<h>            Request headers allowed in CORS requests [default: Authorization,Content-Type,X-API-Key,X-Request-ID].
+    --cors-credentials            Allow CORS requests with cookies or credentials.
+    --cors-max-age=<d>            Time browsers may cache a preflight response [default: 10m].
+    --cache-max-age=<d>           Max-age of cached conversions [default: 8760h].
+    --compress-min-size=<n>       Minimum response size in bytes to compress with gzip or deflate [default: 1024].
+    --sunset=<date>               Date when the deprecated unversioned paths stop being served [default: 2027-06-30].
+    --grpc-addr=<a>               Also serve the conversions over gRPC on this address, e.g. :9091.
+    --graphql-max-depth=<n>       Max depth of GraphQL queries, 0 for no limit [default: 6].
+    --graphql-max-complexity=<n>  Max complexity of GraphQL queries, 0 for no limit [default: 1000].
+    --ws-max-conns=<n>            Max open WebSocket connections [default: 1000].
+    --ws-client-conns=<n>         Max open WebSocket connections of a single client [default: 10].
+    --ws-rate=<r>                 Messages per second allowed on each WebSocket connection, 0 for no limit [default: 20].
+    --ws-ping-interval=<d>        Interval between WebSocket pings, a connection without answer for two intervals is closed [default: 30s].
+    --events-buffer=<n>           Events kept for clients that reconnect to /events/stream with Last-Event-ID [default: 1000].
+    --events-heartbeat=<d>        Interval between heartbeats on /events/stream [default: 15s].`
 
 	opts, err := docopt.ParseArgs(usage, args[1:], version)
 	assertor.ErrNil(err, "Failed to pars cli args")
@@ -66,9 +171,98 @@ Options:
 
 	var logger *slog.Logger
 	if format == "json" {
-		logger = slog.New(slog.NewJSONHandler(stdout, nil))
+		logger = slog.New(contextHandler{slog.NewJSONHandler(stdout, nil)})
 	} else {
-		logger = slog.New(slog.NewTextHandler(stdout, nil))
+		logger = slog.New(contextHandler{slog.NewTextHandler(stdout, nil)})
+	}
+
+	if migrate, _ := opts.Bool("migrate"); migrate {
+		return runMigrate(ctx, getenv, logger, opts)
+	}
+	if keys, _ := opts.Bool("keys"); keys {
+		return runKeys(getenv, stdout, format, opts)
+	}
+
+	logSample, _ := opts.String("--log-sample")
+	logSampling, err := parseSampling(logSample)
+	if err != nil {
+		return err
+	}
+
+	metricsAddr, _ := opts.String("--metrics-addr")
+
+	var keys *apikey.Store
+	if keysPath := keysFile(getenv, opts); keysPath != "" {
+		keys, err = apikey.Open(keysPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	jwt, policy, err := jwtConfig(getenv, opts)
+	if err != nil {
+		return err
+	}
+
+	limiter, quota, trustedProxies, err := rateLimitConfig(opts)
+	if err != nil {
+		return err
+	}
+	authFailures, err := authFailuresConfig(opts)
+	if err != nil {
+		return err
+	}
+
+	limits, err := parseServerLimits(opts)
+	if err != nil {
+		return err
+	}
+	maxBodyStr, _ := opts.String("--max-body")
+	maxBody, err := strconv.ParseInt(maxBodyStr, 10, 64)
+	if err != nil || maxBody < 0 {
+		return fmt.Errorf("server: invalid --max-body %q", maxBodyStr)
+	}
+	bodyLimit, _ := opts.String("--body-limit")
+	bodyLimits, err := parseBodyLimits(bodyLimit)
+	if err != nil {
+		return err
+	}
+
+	corsConf, err := parseCORS(opts)
+	if err != nil {
+		return err
+	}
+
+	cacheMaxAgeStr, _ := opts.String("--cache-max-age")
+	cacheMaxAge, err := time.ParseDuration(cacheMaxAgeStr)
+	if err != nil || cacheMaxAge <= 0 {
+		return fmt.Errorf("cache: invalid --cache-max-age %q", cacheMaxAgeStr)
+	}
+
+	compressMinSizeStr, _ := opts.String("--compress-min-size")
+	compressMinSize, err := strconv.Atoi(compressMinSizeStr)
+	if err != nil || compressMinSize < 0 {
+		return fmt.Errorf("compress: invalid --compress-min-size %q", compressMinSizeStr)
+	}
+
+	sunsetStr, _ := opts.String("--sunset")
+	sunset, err := time.Parse(time.DateOnly, sunsetStr)
+	if err != nil {
+		return fmt.Errorf("versions: --sunset must be a date like 2027-06-30, got %q", sunsetStr)
+	}
+
+	gqlLimits, err := graphqlLimits(opts)
+	if err != nil {
+		return err
+	}
+
+	ws, err := wsConfig(opts)
+	if err != nil {
+		return err
+	}
+	events, err := eventsConfig(opts)
+	if err != nil {
+		return err
 	}
 
 	portStr := getenv("HTTP_PORT")
@@ -79,6 +273,11 @@ Options:
 	assertor.IntNot(port, 22, "port cannot be 22")
 	assertor.PortClosed(port, "the port is closed")
 
+	tlsConf, err := tlsConfig(opts, host, logger)
+	if err != nil {
+		return err
+	}
+
 	app := app.NewApp(stderr, stdout, format)
 
 	mux := http.NewServeMux()
@@ -89,28 +288,129 @@ Options:
 		mux:    mux,
 		stdout: stdout,
 		stderr: stderr,
+
+		logSampling: logSampling,
+		metrics:     newHTTPMetrics(app.ErrorCounts),
+		metricsAddr: metricsAddr,
+		keys:        keys,
+		jwt:         jwt,
+		policy:      policy,
+
+		limiter:        limiter,
+		quota:          quota,
+		authFailures:   authFailures,
+		trustedProxies: trustedProxies,
+		mtls:           tlsConf != nil && tlsConf.ClientCAs != nil,
+		maxBody:        maxBody,
+		bodyLimits:     bodyLimits,
+		corsConfig:     corsConf,
+		cacheMaxAge:    cacheMaxAge,
+
+		compressMinSize: compressMinSize,
+		sunset:          sunset,
+		graphqlLimits:   gqlLimits,
+		ws:              ws,
+		events:          events,
+		started:         time.Now(),
+		config:          redactConfig(opts),
 	}
 
 	h.SetRoutes()
+	h.SetMiddlewares()
+	if policy != nil {
+		if err := policy.Check(h.routePatterns()); err != nil {
+			return err
+		}
+	}
 
 	server := &http.Server{
-		Addr:    fmt.Sprintf("%s:%d", host, port),
-		Handler: mux,
+		Addr:      fmt.Sprintf("%s:%d", host, port),
+		Handler:   h.Handler(),
+		TLSConfig: tlsConf,
 	}
+	limits.apply(server)
+	// Shutdown no cierra las conexiones tomadas por los WebSocket
+	server.RegisterOnShutdown(h.ws.Shutdown)
+	server.RegisterOnShutdown(h.events.Shutdown)
 
 	// Iniciar el servidor en una goroutine
 	go func() {
+		if tlsConf != nil {
+			// el certificado ya esta en TLSConfig
+			errChan <- server.ListenAndServeTLS("", "")
+			return
+		}
 		errChan <- server.ListenAndServe()
 	}()
 
+	servers := []*http.Server{server}
+	if metricsAddr != "" {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("GET /metrics", h.metrics.registry.Handler())
+		admin := &http.Server{
+			Addr:    metricsAddr,
+			Handler: adminMux,
+		}
+		limits.apply(admin)
+		servers = append(servers, admin)
+		go func() {
+			errChan <- admin.ListenAndServe()
+		}()
+	}
+
+	// el servidor gRPC comparte la instancia de app, el TLS y las credenciales de la api http
+	var grpcServer *grpc_adapter.GRPC
+	if grpcAddr, _ := opts.String("--grpc-addr"); grpcAddr != "" {
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			for _, s := range servers {
+				s.Close()
+			}
+			return fmt.Errorf("grpc: %w", err)
+		}
+		grpcServer = grpc_adapter.New(app, grpc_adapter.Options{
+			Logger:    logger,
+			TLS:       tlsConf,
+			Authorize: h.grpcAuthorize(),
+			Limit:     h.grpcLimit(),
+		})
+		go func() {
+			errChan <- grpcServer.Serve(lis)
+		}()
+	}
+
+	if quota != nil {
+		stopFlush := h.flushQuota(10 * time.Second)
+		defer stopFlush()
+	}
+
 	// Esperar por cancelación del contexto o error del servidor
 	select {
 	case <-ctx.Done():
 		// Dar un timeout para el shutdown graceful
 		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer shutdownCancel()
-		return server.Shutdown(shutdownCtx)
+		var shutdownErr error
+		for _, s := range servers {
+			if err := s.Shutdown(shutdownCtx); err != nil && shutdownErr == nil {
+				shutdownErr = err
+			}
+		}
+		if grpcServer != nil {
+			if err := grpcServer.Shutdown(shutdownCtx); err != nil && shutdownErr == nil {
+				shutdownErr = err
+			}
+		}
+		return shutdownErr
 	case err := <-errChan:
+		h.ws.Shutdown()
+		h.events.Shutdown()
+		for _, s := range servers {
+			s.Close()
+		}
+		if grpcServer != nil {
+			grpcServer.Close()
+		}
 		return err
 	}
 }
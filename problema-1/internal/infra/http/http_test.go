@@ -198,7 +198,102 @@ func TestGetIDByPatent(t *testing.T) {
 	}
 }
 
-func setupTestServer(t *testing.T, ctx context.Context) string {
+func TestProblemResponses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	baseURL := setupTestServer(t, ctx)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedErr  string
+	}{
+		{"id no numerico", http.MethodGet, "/patente/abc", http.StatusBadRequest, "invalid_id"},
+		{"id fuera de rango", http.MethodGet, "/patente/0", http.StatusBadRequest, "invalid_range"},
+		{"patente con formato inválido", http.MethodGet, "/id/AAAA0000", http.StatusBadRequest, "bad_format"},
+		{"ruta inexistente", http.MethodGet, "/no-existe", http.StatusNotFound, "not_found"},
+		{"método no permitido", http.MethodPost, "/patente/1", http.StatusMethodNotAllowed, "method_not_allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, baseURL+tt.path, nil)
+			if err != nil {
+				t.Fatalf("Error al crear la solicitud: %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Error al realizar la solicitud: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.expectedCode {
+				t.Errorf("Código de estado esperado %d, pero obtuvo %d", tt.expectedCode, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("Content-Type esperado application/problem+json, pero obtuvo %q", got)
+			}
+			if tt.expectedCode == http.StatusMethodNotAllowed && resp.Header.Get("Allow") == "" {
+				t.Errorf("Se esperaba el header Allow en la respuesta 405")
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("Error al decodificar la respuesta JSON: %v", err)
+			}
+			if body["code"] != tt.expectedErr {
+				t.Errorf("Código de error esperado %q, pero obtuvo %v", tt.expectedErr, body["code"])
+			}
+			if body["status"] != float64(tt.expectedCode) {
+				t.Errorf("Status esperado %d en el cuerpo, pero obtuvo %v", tt.expectedCode, body["status"])
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	baseURL := setupTestServer(t, ctx)
+
+	for _, path := range []string{"/patente/1", "/id/AAAA0000", "/id/AAAA0000"} {
+		resp, err := http.Get(baseURL + path)
+		if err != nil {
+			t.Fatalf("Error al realizar la solicitud: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(baseURL + "/metrics")
+	if err != nil {
+		t.Fatalf("Error al realizar la solicitud: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Error al leer la respuesta: %v", err)
+	}
+
+	expected := []string{
+		`http_requests_total{method="GET",route="GET /patente/{id}",status="200"} 1`,
+		`http_requests_total{method="GET",route="GET /id/{patente}",status="400"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="GET /patente/{id}",status="200"} 1`,
+		`patentes_conversion_errors_total{reason="bad_format"} 2`,
+		"# TYPE go_goroutines gauge",
+		`build_info{goversion=`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("Métrica esperada %q no encontrada en:\n%s", line, body)
+		}
+	}
+}
+
+func setupTestServer(t *testing.T, ctx context.Context, extraArgs ...string) string {
 	pwd := filepath.Dir(filepath.Dir(os.Getenv("PWD")))
 	if pwd == "" {
 		t.Fatal("no PWD env var")
@@ -221,6 +316,7 @@ func setupTestServer(t *testing.T, ctx context.Context) string {
 		"http",
 		"--host=127.0.0.1",
 	}
+	args = append(args, extraArgs...)
 
 	errChan := make(chan error, 1)
 	go func() {
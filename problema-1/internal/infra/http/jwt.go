@@ -0,0 +1,65 @@
+package http_adapter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/jwtauth"
+	"github.com/docopt/docopt-go"
+)
+
+// jwtConfig arma el verificador de JWT con las llaves de --jwt-jwks, --jwt-key y el secreto HS256
+// de JWT_HS256_SECRET, que va en una variable de entorno para no quedar en la lista de procesos.
+// Sin llaves retorna nil y los JWT no se aceptan.
+func jwtConfig(getenv func(string) string, opts docopt.Opts) (*jwtauth.Verifier, *jwtauth.Policy, error) {
+	var keys []jwtauth.Key
+	if path, _ := opts.String("--jwt-jwks"); path != "" {
+		jwks, err := jwtauth.LoadJWKS(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, jwks...)
+	}
+	if path, _ := opts.String("--jwt-key"); path != "" {
+		key, err := jwtauth.LoadPEM(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+	}
+	if secret := getenv("JWT_HS256_SECRET"); secret != "" {
+		key, err := jwtauth.NewHMACKey("", []byte(secret))
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	var policy *jwtauth.Policy
+	if path, _ := opts.String("--jwt-policy"); path != "" {
+		if len(keys) == 0 {
+			return nil, nil, errors.New("jwt: --jwt-policy requires keys to verify tokens")
+		}
+		var err error
+		policy, err = jwtauth.LoadPolicy(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil, nil
+	}
+
+	issuer, _ := opts.String("--jwt-issuer")
+	audience, _ := opts.String("--jwt-audience")
+	verifier, err := jwtauth.NewVerifier(jwtauth.Options{
+		Keys:     keys,
+		Issuer:   issuer,
+		Audience: audience,
+		Leeway:   30 * time.Second,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return verifier, policy, nil
+}
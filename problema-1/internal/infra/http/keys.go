@@ -0,0 +1,96 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	"github.com/docopt/docopt-go"
+)
+
+// keysFile retorna la ruta del archivo de api keys de la opcion --keys o de API_KEYS_FILE.
+func keysFile(getenv func(string) string, opts docopt.Opts) string {
+	if path, _ := opts.String("--keys"); path != "" {
+		return path
+	}
+	return getenv("API_KEYS_FILE")
+}
+
+// runKeys ejecuta los comandos keys create, list y revoke sobre el archivo de api keys.
+func runKeys(getenv func(string) string, stdout io.Writer, format string, opts docopt.Opts) error {
+	path := keysFile(getenv, opts)
+	if path == "" {
+		return fmt.Errorf("keys: --keys or API_KEYS_FILE is required")
+	}
+	store, err := apikey.Open(path)
+	if err != nil {
+		return err
+	}
+
+	if create, _ := opts.Bool("create"); create {
+		label, _ := opts.String("--label")
+		scopesStr, _ := opts.String("--scopes")
+		expiresStr, _ := opts.String("--expires")
+
+		var scopes []string
+		for _, scope := range strings.Split(scopesStr, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+		lifetime, err := time.ParseDuration(expiresStr)
+		if err != nil || lifetime < 0 {
+			return fmt.Errorf("keys: --expires must be a positive duration like 720h")
+		}
+		var expiresAt time.Time
+		if lifetime > 0 {
+			expiresAt = time.Now().Add(lifetime)
+		}
+
+		token, key, err := store.Create(label, scopes, expiresAt)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Created key %s (%s), store the secret now, it will not be shown again:\n%s\n", key.ID, key.Label, token)
+		return nil
+	}
+
+	if revoke, _ := opts.Bool("revoke"); revoke {
+		id, _ := opts.String("<id>")
+		if err := store.Revoke(id); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "Revoked key %s\n", id)
+		return nil
+	}
+
+	now := time.Now()
+	keys := store.List()
+	if format == "json" {
+		type listed struct {
+			apikey.Key
+			Status string `json:"status"`
+		}
+		out := make([]listed, len(keys))
+		for i, key := range keys {
+			key.Hash = ""
+			out[i] = listed{Key: key, Status: key.Status(now)}
+		}
+		return json.NewEncoder(stdout).Encode(out)
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tLABEL\tSCOPES\tSTATUS\tEXPIRES")
+	for _, key := range keys {
+		expires := "never"
+		if key.ExpiresAt != nil {
+			expires = key.ExpiresAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", key.ID, key.Label, strings.Join(key.Scopes, ","), key.Status(now), expires)
+	}
+	return tw.Flush()
+}
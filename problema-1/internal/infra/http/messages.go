@@ -0,0 +1,238 @@
+package http_adapter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/migrate"
+)
+
+// Idiomas de los mensajes de error. Sin un idioma soportado se usa ingles, el de los mensajes que
+// respondia la api antes del catalogo.
+const (
+	langEN = "en"
+	langES = "es"
+)
+
+var languages = []string{langEN, langES}
+
+// Codigos de los errores de la linea de comandos, no los responde la api.
+const (
+	CodeKeyNotFound      = "key_not_found"
+	CodeMigrationLocked  = "migration_locked"
+	CodeChecksumMismatch = "checksum_mismatch"
+	CodeMigrationMissing = "migration_missing"
+	CodeMigrationNoDown  = "migration_no_down"
+)
+
+// cliErrors da codigo a los errores de los comandos keys y migrate para traducirlos.
+var cliErrors = map[error]string{
+	apikey.ErrNotFound:          CodeKeyNotFound,
+	migrate.ErrLocked:           CodeMigrationLocked,
+	migrate.ErrChecksumMismatch: CodeChecksumMismatch,
+	migrate.ErrMissingSource:    CodeMigrationMissing,
+	migrate.ErrNoDown:           CodeMigrationNoDown,
+}
+
+// messages es el catalogo de mensajes por codigo de error e idioma. Cada codigo debe tener todos
+// los idiomas, hay un test que lo comprueba.
+var messages = map[string]map[string]string{
+	app.CodeInvalidRange: {
+		langEN: "the id is out of range, valid ids go from 1 to 456976000",
+		langES: "el id esta fuera de rango, los ids validos van de 1 a 456976000",
+	},
+	app.CodeEmpty: {
+		langEN: "the plate cannot be empty",
+		langES: "la patente no puede estar vacia",
+	},
+	app.CodeBadFormat: {
+		langEN: "the plate must have 4 letters and 3 digits, like AAAA000",
+		langES: "la patente debe tener 4 letras y 3 digitos, como AAAA000",
+	},
+	CodeInvalidID: {
+		langEN: "the id must be a number greater than 0",
+		langES: "el id debe ser un numero mayor que 0",
+	},
+	CodeNotFound: {
+		langEN: "the requested route does not exist",
+		langES: "la ruta solicitada no existe",
+	},
+	CodeMethodNotAllowed: {
+		langEN: "the route does not accept this method",
+		langES: "la ruta no acepta este metodo",
+	},
+	CodeInternal: {
+		langEN: "internal error",
+		langES: "error interno",
+	},
+	CodeInvalidRequest: {
+		langEN: "the request is invalid",
+		langES: "el request no es valido",
+	},
+	CodeInvalidArgument: {
+		langEN: "an argument is invalid",
+		langES: "un argumento no es valido",
+	},
+	CodeUpgradeRequired: {
+		langEN: "this endpoint only accepts WebSocket connections",
+		langES: "esta ruta solo acepta conexiones WebSocket",
+	},
+	CodeTooManyConnections: {
+		langEN: "too many open WebSocket connections, try again later",
+		langES: "hay demasiadas conexiones WebSocket abiertas, intente mas tarde",
+	},
+	CodeClientClosed: {
+		langEN: "the client closed the request before the response",
+		langES: "el cliente cerro el request antes de la respuesta",
+	},
+	CodeTimeout: {
+		langEN: "the request took too long, try again later",
+		langES: "el request tardo demasiado, intente mas tarde",
+	},
+	CodeUnauthorized: {
+		langEN: "valid credentials are required",
+		langES: "se requieren credenciales validas",
+	},
+	CodeForbidden: {
+		langEN: "the credentials do not allow this request",
+		langES: "las credenciales no permiten este request",
+	},
+	CodeUnsupportedEncoding: {
+		langEN: "the request body encoding is not supported",
+		langES: "la codificacion del body no esta soportada",
+	},
+	CodeBodyTooLarge: {
+		langEN: "the request body is too large",
+		langES: "el body del request es demasiado grande",
+	},
+	CodeNotAcceptable: {
+		langEN: "none of the formats in Accept is supported",
+		langES: "ninguno de los formatos de Accept esta soportado",
+	},
+	CodeRateLimited: {
+		langEN: "too many requests, try again later",
+		langES: "demasiados requests, intente mas tarde",
+	},
+	CodeQuotaExceeded: {
+		langEN: "the daily quota is exhausted",
+		langES: "se agoto la cuota diaria",
+	},
+	CodeKeyNotFound: {
+		langEN: "the api key does not exist",
+		langES: "la api key no existe",
+	},
+	CodeMigrationLocked: {
+		langEN: "another migration is running",
+		langES: "otra migracion esta en curso",
+	},
+	CodeChecksumMismatch: {
+		langEN: "an applied migration was modified, its checksum does not match",
+		langES: "una migracion aplicada fue modificada, su checksum no coincide",
+	},
+	CodeMigrationMissing: {
+		langEN: "an applied migration is missing from the migrations path",
+		langES: "falta una migracion aplicada en el path de migraciones",
+	},
+	CodeMigrationNoDown: {
+		langEN: "the migration has no down file",
+		langES: "la migracion no tiene archivo down",
+	},
+}
+
+// negotiateLang elige el idioma de Accept-Language con mayor q, es-CL y cualquier otra variante
+// de es usan es.
+func negotiateLang(header string) string {
+	best, bestQ := langEN, 0.0
+	for _, item := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		for _, lang := range languages {
+			if primary == lang && q > bestQ {
+				best, bestQ = lang, q
+			}
+		}
+	}
+	return best
+}
+
+// LangFromEnv elige el idioma de la linea de comandos como lo hace gettext, LANG=es_CL.UTF-8 usa
+// es.
+func LangFromEnv(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := getenv(name)
+		if value == "" {
+			continue
+		}
+		primary := strings.ToLower(value)
+		if i := strings.IndexAny(primary, "_.@-"); i >= 0 {
+			primary = primary[:i]
+		}
+		for _, lang := range languages {
+			if primary == lang {
+				return lang
+			}
+		}
+		return langEN
+	}
+	return langEN
+}
+
+// langFrom retorna el idioma del request elegido por el access log.
+func langFrom(ctx context.Context) string {
+	if info := infoFrom(ctx); info != nil && info.lang != "" {
+		return info.lang
+	}
+	return langEN
+}
+
+// message retorna el mensaje del codigo en el idioma del request. En ingles se mantiene detail,
+// que suele ser mas especifico que el del catalogo.
+func message(ctx context.Context, code string, detail string) string {
+	lang := langFrom(ctx)
+	if lang == langEN && detail != "" {
+		return detail
+	}
+	if text, ok := messages[code][lang]; ok {
+		return text
+	}
+	return detail
+}
+
+// appMessage es el mensaje de un error de dominio en el idioma del request. El texto del error
+// incluye la operacion, como "patent to id: ...", y no se muestra a los usuarios.
+func appMessage(ctx context.Context, err error) string {
+	if text, ok := messages[app.ErrorCode(err)][langFrom(ctx)]; ok {
+		return text
+	}
+	return err.Error()
+}
+
+// ErrorMessage traduce los errores de la linea de comandos que tienen codigo, los demas y los en
+// ingles se muestran como estan.
+func ErrorMessage(err error, lang string) string {
+	if lang == langEN {
+		return err.Error()
+	}
+	for target, code := range cliErrors {
+		// el error original tiene detalles como la version de la migracion
+		if text, ok := messages[code][lang]; ok && errors.Is(err, target) {
+			return text + " (" + err.Error() + ")"
+		}
+	}
+	if text, ok := messages[app.ErrorCode(err)][lang]; ok {
+		return text
+	}
+	return err.Error()
+}
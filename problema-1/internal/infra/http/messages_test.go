@@ -0,0 +1,166 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/migrate"
+)
+
+// errorCodes retorna los valores de las constantes Code* declaradas en los archivos de dir, asi un
+// codigo nuevo sin traducciones hace fallar el test aunque nadie lo agregue al catalogo.
+func errorCodes(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	codes := map[string]string{}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, name := range spec.Names {
+				if !strings.HasPrefix(name.Name, "Code") || i >= len(spec.Values) {
+					continue
+				}
+				if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					codes[name.Name], _ = strconv.Unquote(lit.Value)
+				}
+			}
+			return true
+		})
+	}
+	return codes
+}
+
+func TestMessagesComplete(t *testing.T) {
+	codes := errorCodes(t, ".")
+	for name, code := range errorCodes(t, "../../app") {
+		codes["app."+name] = code
+	}
+	if len(codes) < 20 {
+		t.Fatalf("Expected the error codes of the http and app packages, got %v", codes)
+	}
+
+	for name, code := range codes {
+		for _, lang := range languages {
+			if messages[code][lang] == "" {
+				t.Errorf("%s (%s) has no %s message", name, code, lang)
+			}
+		}
+	}
+	for code, translations := range messages {
+		if len(translations) != len(languages) {
+			t.Errorf("%s has messages in %d languages, expected %d", code, len(translations), len(languages))
+		}
+	}
+}
+
+func TestNegotiateLang(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", langEN},
+		{"es-CL", langES},
+		{"es-CL,es;q=0.9,en;q=0.8", langES},
+		{"en-US,es;q=0.5", langEN},
+		{"fr-FR,es;q=0.3", langES},
+		{"ES", langES},
+		{"es;q=0,en", langEN},
+		{"de", langEN},
+		{"es;q=abc", langEN},
+	}
+	for _, tt := range tests {
+		if got := negotiateLang(tt.header); got != tt.expected {
+			t.Errorf("Expected %s for %q, got %s", tt.expected, tt.header, got)
+		}
+	}
+}
+
+func TestLangFromEnv(t *testing.T) {
+	tests := []struct {
+		env      map[string]string
+		expected string
+	}{
+		{map[string]string{}, langEN},
+		{map[string]string{"LANG": "es_CL.UTF-8"}, langES},
+		{map[string]string{"LANG": "es_CL.UTF-8", "LC_ALL": "C"}, langEN},
+		{map[string]string{"LC_MESSAGES": "es", "LANG": "en_US.UTF-8"}, langES},
+	}
+	for _, tt := range tests {
+		if got := LangFromEnv(func(name string) string { return tt.env[name] }); got != tt.expected {
+			t.Errorf("Expected %s for %v, got %s", tt.expected, tt.env, got)
+		}
+	}
+}
+
+func TestLocalizedProblems(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptLanguage string
+		expectedLang   string
+		expectedDetail string
+	}{
+		{"domain error in spanish", "/v1/id/nope", "es-CL,es;q=0.9", langES, "la patente debe tener 4 letras y 3 digitos, como AAAA000"},
+		{"domain error in english", "/v1/id/nope", "", langEN, "the plate must have 4 letters and 3 digits, like AAAA000"},
+		{"adapter error in spanish", "/v1/patente/abc", "es", langES, "el id debe ser un numero mayor que 0"},
+		{"adapter error keeps its english detail", "/v1/patente/abc", "en", langEN, "id must be a valid number"},
+		{"routing error in spanish", "/nope", "es", langES, "la ruta solicitada no existe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			var body problem
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body.Detail != tt.expectedDetail {
+				t.Errorf("Expected detail %q, got %q", tt.expectedDetail, body.Detail)
+			}
+			if got := rec.Header().Get("Content-Language"); got != tt.expectedLang {
+				t.Errorf("Expected Content-Language %s, got %s", tt.expectedLang, got)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	wrapped := fmt.Errorf("%w: version 3 (users)", migrate.ErrNoDown)
+	if got := ErrorMessage(wrapped, langES); got != "la migracion no tiene archivo down (migrate: migration has no down file: version 3 (users))" {
+		t.Errorf("Unexpected spanish message %q", got)
+	}
+	if got := ErrorMessage(wrapped, langEN); got != wrapped.Error() {
+		t.Errorf("Expected the original english message, got %q", got)
+	}
+	if got := ErrorMessage(fmt.Errorf("cache: invalid --cache-max-age"), langES); got != "cache: invalid --cache-max-age" {
+		t.Errorf("Expected errors without code unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package http_adapter
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/metrics"
+)
+
+type httpMetrics struct {
+	registry *metrics.Registry
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+	versions *metrics.CounterVec
+}
+
+// newHTTPMetrics crea el registro de metricas, errorCounts entrega los errores de conversion por codigo.
+func newHTTPMetrics(errorCounts func() map[string]uint64) *httpMetrics {
+	registry := metrics.NewRegistry()
+	metrics.RegisterRuntime(registry)
+	metrics.RegisterBuildInfo(registry, version)
+
+	registry.NewCounterFunc(
+		"patentes_conversion_errors_total",
+		"Failed conversions by reason.",
+		"reason",
+		func() map[string]float64 {
+			counts := map[string]float64{}
+			for reason, count := range errorCounts() {
+				counts[reason] = float64(count)
+			}
+			return counts
+		},
+	)
+
+	return &httpMetrics{
+		registry: registry,
+		requests: registry.NewCounterVec(
+			"http_requests_total",
+			"HTTP requests by method, route and status.",
+			"method", "route", "status",
+		),
+		duration: registry.NewHistogramVec(
+			"http_request_duration_seconds",
+			"HTTP request latency by method, route and status.",
+			nil,
+			"method", "route", "status",
+		),
+		versions: registry.NewCounterVec(
+			"http_requests_by_version_total",
+			"HTTP requests to versioned routes by api version, legacy for unversioned paths.",
+			"version",
+		),
+	}
+}
+
+// instrument cuenta los requests y su latencia. Se usa el patron de la ruta y no la url para que las
+// series no crezcan con cada id distinto.
+func (h *HTTP) instrument(next http.Handler) http.Handler {
+	if h.metrics == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := newResponseWriter(w)
+		next.ServeHTTP(rw, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(rw.status)
+		h.metrics.requests.Inc(r.Method, route, status)
+		h.metrics.duration.Observe(time.Since(start).Seconds(), r.Method, route, status)
+		if version := h.routeVersions[r.Pattern]; version != "" {
+			h.metrics.versions.Inc(version)
+		}
+	})
+}
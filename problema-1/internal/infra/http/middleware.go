@@ -0,0 +1,150 @@
+package http_adapter
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/do-prueba-tecnica/problema-1/pkgs/assertor"
+)
+
+// Middleware envuelve un handler, se componen con Chain.
+type Middleware func(http.Handler) http.Handler
+
+// Chain aplica los middlewares sobre handler, el primero de la lista es el mas externo y por lo
+// tanto el primero en ver el request.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// Use agrega middlewares al final de la cadena del adapter.
+func (h *HTTP) Use(middlewares ...Middleware) {
+	h.middlewares = append(h.middlewares, middlewares...)
+}
+
+// Handler retorna el mux envuelto en la cadena de middlewares.
+func (h *HTTP) Handler() http.Handler {
+	return Chain(h.routingErrors(h.mux), h.middlewares...)
+}
+
+// responseWriter guarda el status y los bytes escritos para los middlewares que lo necesitan.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw
+	}
+	return &responseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.status = status
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += n
+	return n, err
+}
+
+// Unwrap permite a http.ResponseController llegar al writer original.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack registra el 101 de los WebSocket, despues de tomar la conexion no se puede escribir el
+// status por el ResponseWriter.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.status = http.StatusSwitchingProtocols
+		rw.wroteHeader = true
+	}
+	return conn, brw, err
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// recoverer convierte un panic en un handler en una respuesta 500 problem+json con un id de correlacion
+// que tambien queda en el log, asi el cliente puede reportar el error y lo podemos encontrar.
+func (h *HTTP) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := newResponseWriter(w)
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// ErrAbortHandler es la forma de net/http de cortar la respuesta, se debe propagar
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			var assertion assertor.AssertionError
+			err, isErr := recovered.(error)
+			if !isErr {
+				err = fmt.Errorf("%v", recovered)
+			}
+
+			// usamos el id del access log para poder cruzar el panic con la linea del request
+			correlationID := RequestIDFrom(r.Context())
+			if correlationID == "" {
+				correlationID = newID()
+			}
+			h.logger.ErrorContext(r.Context(), "Panic recovered",
+				slog.String("correlation_id", correlationID),
+				slog.String("method", r.Method),
+				slog.String("url", r.URL.String()),
+				slog.String("panic", err.Error()),
+				slog.Bool("assertion", errors.As(err, &assertion)),
+				slog.String("stack", string(debug.Stack())),
+			)
+
+			// si el handler ya empezo a responder no podemos cambiar el status
+			if rw.wroteHeader {
+				return
+			}
+			problem := problem{
+				Type:          "/problems/" + CodeInternal,
+				Title:         http.StatusText(http.StatusInternalServerError),
+				Status:        http.StatusInternalServerError,
+				Instance:      r.URL.Path,
+				Code:          CodeInternal,
+				CorrelationID: correlationID,
+			}
+			rw.Header().Set("Content-Type", problemContentType)
+			rw.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(rw).Encode(problem)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
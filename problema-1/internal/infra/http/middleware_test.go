@@ -0,0 +1,111 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/pkgs/assertor"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), mark("first"), mark("second"))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(order, ","); got != "first,second,handler" {
+		t.Errorf("Expected order first,second,handler, got %s", got)
+	}
+}
+
+func TestRecoverer(t *testing.T) {
+	logs := &strings.Builder{}
+	h := &HTTP{
+		logger: slog.New(slog.NewJSONHandler(logs, nil)),
+		mux:    http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /assert", func(w http.ResponseWriter, r *http.Request) {
+		assertor.IntGreater(0, 1, "zero is not greater than one")
+	})
+	h.mux.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	h.mux.HandleFunc("GET /partial", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "partial")
+		panic("boom after write")
+	})
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		assertion    bool
+	}{
+		{"assertor panic", "/assert", http.StatusInternalServerError, true},
+		{"plain panic", "/panic", http.StatusInternalServerError, false},
+		{"panic after write keeps status", "/partial", http.StatusOK, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+
+			entry := findLog(t, logs.String(), "Panic recovered")
+			if entry["assertion"] != tt.assertion {
+				t.Errorf("Expected assertion=%v in log, got %v", tt.assertion, entry["assertion"])
+			}
+
+			if tt.expectedCode != http.StatusInternalServerError {
+				return
+			}
+			var body map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			if body["code"] != CodeInternal {
+				t.Errorf("Expected code %q, got %v", CodeInternal, body["code"])
+			}
+			if body["correlation_id"] == "" || body["correlation_id"] != entry["correlation_id"] {
+				t.Errorf("Expected matching correlation id in body and log, got %v and %v", body["correlation_id"], entry["correlation_id"])
+			}
+		})
+	}
+}
+
+// findLog busca en la salida JSON del logger la primera linea con el mensaje msg.
+func findLog(t *testing.T, output string, msg string) map[string]any {
+	t.Helper()
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to decode log line %q: %v", line, err)
+		}
+		if entry["msg"] == msg {
+			return entry
+		}
+	}
+	t.Fatalf("No log line with msg %q in %q", msg, output)
+	return nil
+}
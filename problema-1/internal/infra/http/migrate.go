@@ -0,0 +1,71 @@
+package http_adapter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/migrate"
+	"github.com/docopt/docopt-go"
+)
+
+// runMigrate ejecuta el comando migrate, la conexion se toma de DATABASE_DRIVER y DATABASE_URL.
+func runMigrate(ctx context.Context, getenv func(string) string, logger *slog.Logger, opts docopt.Opts) error {
+	steps, err := opts.Int("--steps")
+	if err != nil {
+		return fmt.Errorf("migrate: --steps must be a number: %w", err)
+	}
+	direction, err := opts.String("--direction")
+	if err != nil {
+		return fmt.Errorf("migrate: invalid --direction: %w", err)
+	}
+	path, err := opts.String("--path")
+	if err != nil {
+		return fmt.Errorf("migrate: invalid --path: %w", err)
+	}
+	dryRun, _ := opts.Bool("--dry-run")
+	// con steps 0 se mueven todas las migraciones, revertir todas tiene que pedirse explicitamente
+	if direction == migrate.DirectionDown && steps == 0 {
+		return fmt.Errorf("migrate: --direction=down requires --steps greater than 0")
+	}
+
+	driverName := getenv("DATABASE_DRIVER")
+	dsn := getenv("DATABASE_URL")
+	if driverName == "" || dsn == "" {
+		return fmt.Errorf("migrate: DATABASE_DRIVER and DATABASE_URL env vars are required")
+	}
+	if !slices.Contains(sql.Drivers(), driverName) {
+		return fmt.Errorf("migrate: DATABASE_DRIVER %q is not registered, available drivers: %s", driverName, strings.Join(sql.Drivers(), ", "))
+	}
+
+	migrations, err := migrate.Load(os.DirFS(path), ".")
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return fmt.Errorf("migrate: opening database: %w", err)
+	}
+	defer db.Close()
+
+	migrator := migrate.New(db, migrate.Options{
+		Bindvar: migrate.BindvarFor(driverName),
+		DryRun:  dryRun,
+		Logger:  logger,
+	})
+	done, err := migrator.Migrate(ctx, direction, steps, migrations)
+	if err != nil {
+		return err
+	}
+	logger.Info("Migrations finished",
+		slog.String("direction", direction),
+		slog.Int("count", len(done)),
+		slog.Bool("dry_run", dryRun),
+	)
+	return nil
+}
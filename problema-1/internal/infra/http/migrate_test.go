@@ -0,0 +1,46 @@
+package http_adapter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMigrateDownRequiresSteps(t *testing.T) {
+	getenv := func(string) string { return "" }
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"down without steps", []string{"--direction=down"}, "--direction=down requires --steps greater than 0"},
+		// pasa la validacion y falla recien por la conexion
+		{"down with steps", []string{"--direction=down", "--steps=1"}, "DATABASE_DRIVER and DATABASE_URL env vars are required"},
+		{"up without steps", []string{"--direction=up"}, "DATABASE_DRIVER and DATABASE_URL env vars are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"http", "migrate", "--path=."}, tt.args...)
+			err := Run(context.Background(), getenv, strings.NewReader(""), &strings.Builder{}, &strings.Builder{}, args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMigrateUnknownDriver(t *testing.T) {
+	getenv := func(key string) string {
+		switch key {
+		case "DATABASE_DRIVER":
+			return "filedb"
+		case "DATABASE_URL":
+			return "db.json"
+		}
+		return ""
+	}
+	err := Run(context.Background(), getenv, strings.NewReader(""), &strings.Builder{}, &strings.Builder{}, []string{"http", "migrate", "--path=."})
+	if err == nil || !strings.Contains(err.Error(), `DATABASE_DRIVER "filedb" is not registered`) {
+		t.Errorf("Expected an unregistered driver error, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package http_adapter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const CodeNotAcceptable = "not_acceptable"
+
+// encoder codifica una respuesta en un formato, format es el valor de ?format=.
+type encoder struct {
+	format      string
+	contentType string
+	encode      func(v response) ([]byte, error)
+}
+
+// encoders son los formatos soportados en orden de preferencia, el primero es el default cuando el
+// cliente no pide uno.
+var encoders = []encoder{
+	{"json", "application/json", encodeJSON},
+	{"xml", "application/xml", encodeXML},
+	{"csv", "text/csv", encodeCSV},
+	{"text", "text/plain", encodeText},
+	{"cbor", "application/cbor", encodeCBOR},
+}
+
+func encodeJSON(v response) ([]byte, error) {
+	body, err := json.Marshal(v)
+	return append(body, '\n'), err
+}
+
+func encodeXML(v response) ([]byte, error) {
+	body, err := xml.Marshal(v)
+	return append([]byte(xml.Header), append(body, '\n')...), err
+}
+
+// encodeCSV escribe una fila con los nombres de los campos y otra con los valores.
+func encodeCSV(v response) ([]byte, error) {
+	var names, values []string
+	for _, f := range v.fields() {
+		names = append(names, f.name)
+		values = append(values, fmt.Sprint(f.value))
+	}
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	w.Write(names)
+	w.Write(values)
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// encodeText escribe solo los valores, uno por linea, para clientes que no quieren parsear nada.
+func encodeText(v response) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	for _, f := range v.fields() {
+		fmt.Fprintln(buf, f.value)
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiate elige el encoder segun ?format= o, si no viene, segun el header Accept. Retorna false si
+// ningun formato soportado es aceptable.
+func negotiate(r *http.Request) (encoder, bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		for _, e := range encoders {
+			if e.format == format {
+				return e, true
+			}
+		}
+		return encoder{}, false
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return encoders[0], true
+	}
+	best, bestQ := -1, 0.0
+	for i, e := range encoders {
+		// con la misma calidad gana el primero de encoders
+		if q := acceptQuality(accept, e.contentType); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return encoder{}, false
+	}
+	return encoders[best], true
+}
+
+// acceptQuality retorna el q del rango mas especifico de Accept que incluye al content type.
+func acceptQuality(accept string, contentType string) float64 {
+	typ, _, _ := strings.Cut(contentType, "/")
+	q, specificity := 0.0, -1
+	for _, item := range strings.Split(accept, ",") {
+		mediaRange, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+		var s int
+		switch mediaRange {
+		case contentType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s <= specificity {
+			continue
+		}
+		specificity = s
+		q = 1
+		if value, ok := params["q"]; ok {
+			q = parseQuality(value)
+		}
+	}
+	return q
+}
+
+// parseQuality lee el parametro q de Accept y Accept-Encoding, un valor invalido cuenta como 1.
+func parseQuality(value string) float64 {
+	q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || q < 0 || q > 1 {
+		return 1
+	}
+	return q
+}
+
+// respond codifica la respuesta de una conversion en el formato negociado y la escribe con los
+// headers de cache.
+func (h *HTTP) respond(w http.ResponseWriter, r *http.Request, v response) {
+	e, ok := negotiate(r)
+	if !ok {
+		formats := make([]string, len(encoders))
+		for i, e := range encoders {
+			formats[i] = e.contentType
+		}
+		w.Header().Add("Vary", "Accept")
+		h.writeProblem(w, r, http.StatusNotAcceptable, CodeNotAcceptable,
+			"supported formats are "+strings.Join(formats, ", "))
+		return
+	}
+	body, err := e.encode(v)
+	if err != nil {
+		h.writeAppError(w, r, fmt.Errorf("encoding %s response: %w", e.format, err))
+		return
+	}
+	h.writeCached(w, r, e.contentType, body, "Accept")
+}
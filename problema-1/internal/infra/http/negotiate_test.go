@@ -0,0 +1,90 @@
+package http_adapter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		path         string
+		accept       string
+		expectedCode int
+		contentType  string
+		body         string
+	}{
+		{"default json", "/id/AAAA000", "", http.StatusOK, "application/json", `{"id":1}` + "\n"},
+		{"any type", "/id/AAAA000", "*/*", http.StatusOK, "application/json", `{"id":1}` + "\n"},
+		{"xml", "/id/AAAA000", "application/xml", http.StatusOK, "application/xml", xml.Header + "<conversion><id>1</id></conversion>\n"},
+		{"csv", "/patente/1", "text/csv", http.StatusOK, "text/csv", "patente\nAAAA000\n"},
+		{"text", "/patente/1", "text/plain", http.StatusOK, "text/plain", "AAAA000\n"},
+		{"cbor", "/id/AAAA000", "application/cbor", http.StatusOK, "application/cbor", "\xa1\x62id\x01"},
+		{"quality order", "/patente/1", "application/json;q=0.5, text/plain", http.StatusOK, "text/plain", "AAAA000\n"},
+		{"type wildcard", "/patente/1", "text/*", http.StatusOK, "text/csv", "patente\nAAAA000\n"},
+		{"excluded type", "/patente/1", "*/*, application/json;q=0", http.StatusOK, "application/xml", ""},
+		{"format parameter", "/patente/1?format=text", "application/json", http.StatusOK, "text/plain", "AAAA000\n"},
+		{"unknown format parameter", "/patente/1?format=yaml", "", http.StatusNotAcceptable, problemContentType, ""},
+		{"unsupported accept", "/patente/1", "image/png", http.StatusNotAcceptable, problemContentType, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, rec.Body.String())
+			}
+			if !strings.Contains(strings.Join(rec.Header().Values("Vary"), ","), "Accept") {
+				t.Errorf("Expected Vary: Accept, got %v", rec.Header().Values("Vary"))
+			}
+		})
+	}
+}
+
+func TestEncodeCBOR(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    response
+		expected []byte
+	}{
+		{"small id", IDResponse{ID: 23}, []byte{0xa1, 0x62, 'i', 'd', 0x17}},
+		{"one byte id", IDResponse{ID: 24}, []byte{0xa1, 0x62, 'i', 'd', 0x18, 24}},
+		{"four byte id", IDResponse{ID: app.MaxID}, []byte{0xa1, 0x62, 'i', 'd', 0x1a, 0x1b, 0x3c, 0xe6, 0x80}},
+		{"patent", PatentResponse{Patente: "AAAA000"}, append([]byte{0xa1, 0x67}, "patente\x67AAAA000"...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := encodeCBOR(tt.value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !bytes.Equal(got, tt.expected) {
+				t.Errorf("Expected % x, got % x", tt.expected, got)
+			}
+		})
+	}
+}
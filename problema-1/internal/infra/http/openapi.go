@@ -0,0 +1,228 @@
+package http_adapter
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed docs
+var docsFS embed.FS
+
+// openAPISpec genera la especificacion OpenAPI 3 a partir de routes, los schemas salen de los tipos
+// de respuesta por reflexion.
+func (h *HTTP) openAPISpec() map[string]any {
+	schemas := map[string]any{
+		"Problem": schemaOf(reflect.TypeOf(problem{})),
+	}
+	paths := map[string]any{}
+
+	for _, rt := range h.routes() {
+		addOperation(paths, rt.pattern, h.operation(rt, schemas))
+		if rt.legacy {
+			op := h.operation(rt, schemas)
+			op["operationId"] = op["operationId"].(string) + "Legacy"
+			op["deprecated"] = true
+			op["description"] = "Ruta sin version, usar " + rt.pattern
+			addOperation(paths, legacyPattern(rt.pattern), op)
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Patentes",
+			"description": "Conversion entre patentes y sus ids",
+			"version":     version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": apiKeyHeader},
+				"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+// operation documenta una ruta, los schemas de sus respuestas se agregan a schemas.
+func (h *HTTP) operation(rt route, schemas map[string]any) map[string]any {
+	method, path, _ := strings.Cut(rt.pattern, " ")
+	op := map[string]any{
+		"operationId": operationID(method, path),
+		"summary":     rt.summary,
+	}
+
+	errors := slices.Clone(rt.errors)
+	var params []any
+	for _, p := range rt.params {
+		params = append(params, map[string]any{
+			"name":        p.name,
+			"in":          "path",
+			"required":    true,
+			"description": p.description,
+			"schema":      p.schema,
+			"example":     p.example,
+		})
+	}
+
+	responses := map[string]any{}
+	if rt.response != nil {
+		name := reflect.TypeOf(rt.response).Name()
+		schemas[name] = schemaOf(reflect.TypeOf(rt.response))
+		content := map[string]any{}
+		formats := make([]any, len(encoders))
+		for i, e := range encoders {
+			formats[i] = e.format
+			switch e.format {
+			case "json", "xml":
+				content[e.contentType] = map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/" + name}}
+			case "cbor":
+				content[e.contentType] = map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}}
+			default:
+				content[e.contentType] = map[string]any{"schema": map[string]any{"type": "string"}}
+			}
+		}
+		params = append(params, map[string]any{
+			"name":        "format",
+			"in":          "query",
+			"description": "Formato de la respuesta, tiene prioridad sobre Accept",
+			"schema":      map[string]any{"type": "string", "enum": formats},
+		})
+		responses["200"] = map[string]any{
+			"description": "OK",
+			"headers": map[string]any{
+				"ETag":          map[string]any{"schema": map[string]any{"type": "string"}},
+				"Cache-Control": map[string]any{"schema": map[string]any{"type": "string"}},
+			},
+			"content": content,
+		}
+		responses["304"] = map[string]any{"description": "El ETag de If-None-Match sigue vigente"}
+		errors = append(errors, http.StatusNotAcceptable)
+	} else if rt.websocket {
+		responses["101"] = map[string]any{
+			"description": "Cambia a WebSocket, los mensajes son JSON de texto",
+		}
+	} else {
+		schema := map[string]any{"type": "string"}
+		if rt.contentType == "application/json" {
+			schema = map[string]any{"type": "object"}
+		}
+		responses["200"] = map[string]any{
+			"description": "OK",
+			"content":     map[string]any{rt.contentType: map[string]any{"schema": schema}},
+		}
+	}
+
+	if rt.requestBody != "" {
+		op["requestBody"] = map[string]any{
+			"required": true,
+			"content":  map[string]any{rt.requestBody: map[string]any{"schema": map[string]any{"type": "object"}}},
+		}
+	}
+
+	if rt.scope != "" {
+		op["security"] = []any{
+			map[string]any{"apiKey": []any{}},
+			map[string]any{"bearer": []any{rt.scope}},
+		}
+		errors = append(errors, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	for _, status := range append(errors, http.StatusTooManyRequests, http.StatusInternalServerError) {
+		responses[strconv.Itoa(status)] = map[string]any{
+			"description": http.StatusText(status),
+			"content": map[string]any{problemContentType: map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/Problem"},
+			}},
+		}
+	}
+	op["responses"] = responses
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	return op
+}
+
+// addOperation agrega la operacion al path del patron.
+func addOperation(paths map[string]any, pattern string, op map[string]any) {
+	method, path, _ := strings.Cut(pattern, " ")
+	item, ok := paths[path].(map[string]any)
+	if !ok {
+		item = map[string]any{}
+		paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// operationID arma un id a partir del metodo y el path, GET /patente/{id} es getPatenteId.
+func operationID(method string, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+// schemaOf genera el JSON schema de un struct segun sus tags json.
+func schemaOf(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []any
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		var schema map[string]any
+		switch f.Type.Kind() {
+		case reflect.String:
+			schema = map[string]any{"type": "string"}
+		case reflect.Bool:
+			schema = map[string]any{"type": "boolean"}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			schema = map[string]any{"type": "integer", "minimum": 0}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			schema = map[string]any{"type": "integer"}
+		case reflect.Float32, reflect.Float64:
+			schema = map[string]any{"type": "number"}
+		default:
+			schema = map[string]any{}
+		}
+		properties[name] = schema
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func (h *HTTP) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(h.openAPISpec())
+}
+
+// docs sirve la pagina de documentacion embebida, que carga /openapi.json. Necesita una CSP que
+// permita sus propios scripts y estilos, la de securityHeaders no permite nada.
+func (h *HTTP) docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	sub, _ := fs.Sub(docsFS, "docs")
+	http.StripPrefix("/docs/", http.FileServerFS(sub)).ServeHTTP(w, r)
+}
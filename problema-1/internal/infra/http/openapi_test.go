@@ -0,0 +1,133 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+var patternRX = regexp.MustCompile(`^[A-Z]+ /`)
+
+// parsedRoutePatterns retorna los patrones de ruta escritos en routes.go, incluidos los que alguien
+// registre directo en el mux sin pasar por routes.
+func parsedRoutePatterns(t *testing.T) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "routes.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var patterns []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err == nil && patternRX.MatchString(value) {
+			patterns = append(patterns, value)
+		}
+		return true
+	})
+	return patterns
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.metrics = newHTTPMetrics(func() map[string]uint64 { return nil })
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	rec := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name string `json:"name"`
+				In   string `json:"in"`
+			} `json:"parameters"`
+			Responses map[string]any `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&spec); err != nil {
+		t.Fatalf("Invalid spec: %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("Expected OpenAPI 3, got %q", spec.OpenAPI)
+	}
+
+	patterns := parsedRoutePatterns(t)
+	if len(patterns) == 0 {
+		t.Fatal("Expected route patterns in routes.go")
+	}
+	for _, pattern := range patterns {
+		method, path, _ := strings.Cut(pattern, " ")
+		op, ok := spec.Paths[path][strings.ToLower(method)]
+		if !ok {
+			t.Errorf("Route %s is missing from /openapi.json", pattern)
+			continue
+		}
+		// las rutas WebSocket responden 101 en vez de 200
+		_, ok200 := op.Responses["200"]
+		_, ok101 := op.Responses["101"]
+		if !ok200 && !ok101 {
+			t.Errorf("Route %s has no 200 response", pattern)
+		}
+		// cada comodin del path debe estar documentado
+		for _, match := range regexp.MustCompile(`\{([^}.]+)`).FindAllStringSubmatch(path, -1) {
+			found := false
+			for _, p := range op.Parameters {
+				found = found || (p.In == "path" && p.Name == match[1])
+			}
+			if !found {
+				t.Errorf("Route %s does not document the path parameter %s", pattern, match[1])
+			}
+		}
+	}
+}
+
+func TestDocs(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		path        string
+		contentType string
+	}{
+		{"/docs/", "text/html"},
+		{"/docs/app.js", "text/javascript"},
+		{"/docs/style.css", "text/css"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			if got := rec.Header().Get("Content-Security-Policy"); !strings.Contains(got, "default-src 'self'") {
+				t.Errorf("Expected CSP allowing the page assets, got %q", got)
+			}
+		})
+	}
+}
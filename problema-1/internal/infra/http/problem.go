@@ -0,0 +1,153 @@
+package http_adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+const problemContentType = "application/problem+json"
+
+// Codigos de error propios del adapter, los de dominio vienen de app.
+const (
+	CodeInvalidID        = "invalid_id"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeInternal         = "internal_error"
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidArgument  = "invalid_argument"
+	CodeUpgradeRequired  = "upgrade_required"
+	// CodeTooManyConnections es el limite de conexiones WebSocket abiertas
+	CodeTooManyConnections = "too_many_connections"
+	// CodeClientClosed es un request que el cliente cancelo antes de recibir la respuesta y
+	// CodeTimeout uno que no termino antes de su deadline
+	CodeClientClosed = "client_closed_request"
+	CodeTimeout      = "timeout"
+)
+
+// statusClientClosed es el status que usa nginx para los requests que el cliente cancelo, no es
+// estandar pero lo entienden los proxies y dashboards.
+const statusClientClosed = 499
+
+// problem es el cuerpo de error de RFC 7807, code y correlation_id son extensiones.
+type problem struct {
+	Type          string `json:"type"`
+	Title         string `json:"title"`
+	Status        int    `json:"status"`
+	Detail        string `json:"detail,omitempty"`
+	Instance      string `json:"instance,omitempty"`
+	Code          string `json:"code"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+func (h *HTTP) writeProblem(w http.ResponseWriter, r *http.Request, status int, code string, detail string) {
+	title := http.StatusText(status)
+	if status == statusClientClosed {
+		title = "Client Closed Request"
+	}
+	p := problem{
+		Type:          "/problems/" + code,
+		Title:         title,
+		Status:        status,
+		Detail:        message(r.Context(), code, detail),
+		Instance:      r.URL.Path,
+		Code:          code,
+		CorrelationID: RequestIDFrom(r.Context()),
+	}
+	if info := infoFrom(r.Context()); info != nil {
+		info.code = code
+	}
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Language", langFrom(r.Context()))
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// writeAppError responde con el codigo del error de dominio, un error sin codigo es un error interno
+// y su texto no se expone al cliente. Un body que excede el limite de limitBody responde 413, un
+// request cancelado por el cliente 499 y uno con el deadline vencido 503.
+func (h *HTTP) writeAppError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		h.writeProblem(w, r, http.StatusRequestEntityTooLarge, CodeBodyTooLarge,
+			fmt.Sprintf("request body is limited to %d bytes", maxBytes.Limit))
+		return
+	}
+	// un request cancelado o vencido no es un error del servicio, se registra sin nivel de error
+	if errors.Is(err, context.Canceled) {
+		h.logger.DebugContext(r.Context(), "Request canceled by the client", "error", err.Error())
+		h.writeProblem(w, r, statusClientClosed, CodeClientClosed, "")
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		h.logger.InfoContext(r.Context(), "Request deadline exceeded", "error", err.Error())
+		h.writeProblem(w, r, http.StatusServiceUnavailable, CodeTimeout, "")
+		return
+	}
+	code := app.ErrorCode(err)
+	if code == "" {
+		h.logger.ErrorContext(r.Context(), "Unexpected app error", "error", err.Error())
+		h.writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "")
+		return
+	}
+	h.writeProblem(w, r, http.StatusBadRequest, code, appMessage(r.Context(), err))
+}
+
+// routingErrors reemplaza las respuestas en texto plano del mux para rutas no encontradas (404) y
+// metodos no permitidos (405) por problemas en JSON. El resto de respuestas del mux sin handler,
+// como las redirecciones de paths no canonicos, se copian tal cual.
+func (h *HTTP) routingErrors(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &recordedResponse{header: http.Header{}, status: http.StatusOK}
+		mux.ServeHTTP(rec, r)
+
+		switch rec.status {
+		case http.StatusNotFound:
+			h.writeProblem(w, r, http.StatusNotFound, CodeNotFound, "no route matches "+r.URL.Path)
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", rec.header.Get("Allow"))
+			h.writeProblem(w, r, http.StatusMethodNotAllowed, CodeMethodNotAllowed, r.Method+" is not allowed on "+r.URL.Path)
+		default:
+			for key, values := range rec.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+		}
+	})
+}
+
+// recordedResponse guarda en memoria una respuesta del mux para poder reescribirla.
+type recordedResponse struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *recordedResponse) Header() http.Header { return rec.header }
+
+func (rec *recordedResponse) WriteHeader(status int) {
+	if rec.wroteHeader {
+		return
+	}
+	rec.status = status
+	rec.wroteHeader = true
+}
+
+func (rec *recordedResponse) Write(b []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(b)
+}
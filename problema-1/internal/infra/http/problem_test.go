@@ -0,0 +1,53 @@
+package http_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWriteAppErrorContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+		expectedErr  string
+		expectedLog  string
+	}{
+		{"canceled", fmt.Errorf("converting: %w", context.Canceled), statusClientClosed, CodeClientClosed, `"level":"INFO","msg":"Request served"`},
+		{"deadline", fmt.Errorf("converting: %w", context.DeadlineExceeded), http.StatusServiceUnavailable, CodeTimeout, `"level":"INFO","msg":"Request deadline exceeded"`},
+		{"unexpected", errors.New("boom"), http.StatusInternalServerError, CodeInternal, `"level":"ERROR","msg":"Unexpected app error"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := &strings.Builder{}
+			h := newTestHTTP(logs)
+			h.mux.HandleFunc("GET /err", func(w http.ResponseWriter, r *http.Request) {
+				h.writeAppError(w, r, tt.err)
+			})
+			h.SetMiddlewares()
+
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/err", nil))
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			var p problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil || p.Code != tt.expectedErr || p.Title == "" {
+				t.Errorf("Expected a %s problem with title, got %s", tt.expectedErr, rec.Body.String())
+			}
+			if !strings.Contains(logs.String(), tt.expectedLog) {
+				t.Errorf("Expected log %s, got %s", tt.expectedLog, logs.String())
+			}
+			if tt.expectedErr != CodeInternal && strings.Contains(logs.String(), `"level":"ERROR"`) {
+				t.Errorf("Expected no error logs, got %s", logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package http_adapter
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
+	"github.com/docopt/docopt-go"
+)
+
+const (
+	CodeRateLimited   = "rate_limited"
+	CodeQuotaExceeded = "quota_exceeded"
+)
+
+// rateLimitConfig arma el limiter y la cuota con --rate, --burst, --quota y --quota-file, cada uno
+// es nil si su limite es 0.
+func rateLimitConfig(opts docopt.Opts) (*ratelimit.Limiter, *ratelimit.Quota, []netip.Prefix, error) {
+	rateStr, _ := opts.String("--rate")
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate < 0 {
+		return nil, nil, nil, fmt.Errorf("rate limit: invalid --rate %q", rateStr)
+	}
+	burstStr, _ := opts.String("--burst")
+	burst, err := strconv.Atoi(burstStr)
+	if err != nil || burst < 0 {
+		return nil, nil, nil, fmt.Errorf("rate limit: invalid --burst %q", burstStr)
+	}
+	quotaStr, _ := opts.String("--quota")
+	daily, err := strconv.ParseInt(quotaStr, 10, 64)
+	if err != nil || daily < 0 {
+		return nil, nil, nil, fmt.Errorf("rate limit: invalid --quota %q", quotaStr)
+	}
+	proxies, _ := opts.String("--trusted-proxies")
+	trustedProxies, err := parseTrustedProxies(proxies)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var limiter *ratelimit.Limiter
+	if rate > 0 {
+		if burst == 0 {
+			burst = int(math.Ceil(rate))
+		}
+		limiter = ratelimit.NewLimiter(rate, burst)
+	}
+
+	var quota *ratelimit.Quota
+	if daily > 0 {
+		path, _ := opts.String("--quota-file")
+		if path == "" {
+			return nil, nil, nil, errors.New("rate limit: --quota requires --quota-file")
+		}
+		quota, err = ratelimit.OpenQuota(path, daily, time.Now())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return limiter, quota, trustedProxies, nil
+}
+
+// authFailuresConfig arma el limiter de autenticaciones fallidas por IP con --auth-failures, nil si
+// es 0.
+func authFailuresConfig(opts docopt.Opts) (*ratelimit.Limiter, error) {
+	value, _ := opts.String("--auth-failures")
+	failures, err := strconv.Atoi(value)
+	if err != nil || failures < 0 {
+		return nil, fmt.Errorf("rate limit: invalid --auth-failures %q", value)
+	}
+	if failures == 0 {
+		return nil, nil
+	}
+	return ratelimit.NewLimiter(float64(failures)/60, failures), nil
+}
+
+// flushQuota guarda la cuota cada interval, la funcion que retorna detiene el guardado y hace un
+// ultimo Flush para no perder los requests del final.
+func (h *HTTP) flushQuota(interval time.Duration) func() {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-ticker.C:
+				if err := h.quota.Flush(); err != nil {
+					h.logger.Error("Failed to save quota", "error", err.Error())
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+		<-stopped
+		if err := h.quota.Flush(); err != nil {
+			h.logger.Error("Failed to save quota", "error", err.Error())
+		}
+	}
+}
+
+// parseTrustedProxies lee una lista de IPs o rangos CIDR separados por coma.
+func parseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxies: invalid address %q", item)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxies: invalid range %q", item)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func (h *HTTP) trusted(addr netip.Addr) bool {
+	for _, prefix := range h.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP retorna la IP del cliente. X-Forwarded-For solo se considera si el request viene de un
+// proxy confiable, y se recorre de derecha a izquierda saltando los proxies confiables porque las
+// entradas de la izquierda las puede inventar el cliente.
+func (h *HTTP) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return host
+	}
+	addr = addr.Unmap()
+	if !h.trusted(addr) {
+		return addr.String()
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// una entrada invalida corta la cadena, lo ultimo confiable es el proxy anterior
+			break
+		}
+		addr = hop.Unmap()
+		if !h.trusted(addr) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+// clientKey identifica al cliente para los limites, los autenticados por su credencial y los
+// anonimos por IP.
+func (h *HTTP) clientKey(r *http.Request) string {
+	if p := PrincipalFrom(r.Context()); p != nil {
+		return p.key()
+	}
+	return "ip:" + h.clientIP(r)
+}
+
+// rateLimit aplica el token bucket y la cuota diaria por cliente, informando el estado en los
+// headers RateLimit-*. El healthcheck no se limita para no afectar a los balanceadores.
+func (h *HTTP) rateLimit(next http.Handler) http.Handler {
+	if h.limiter == nil && h.quota == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := h.mux.Handler(r); pattern == "GET /healthcheck" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key := h.clientKey(r)
+		now := time.Now()
+
+		if h.limiter != nil {
+			d := h.limiter.Allow(key, now)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(d.Reset))
+			if !d.Allowed {
+				w.Header().Set("Retry-After", seconds(d.Reset))
+				h.writeProblem(w, r, http.StatusTooManyRequests, CodeRateLimited, "too many requests")
+				return
+			}
+		}
+
+		if h.quota != nil {
+			remaining, ok := h.quota.Allow(key, now)
+			w.Header().Set("X-Quota-Limit", strconv.FormatInt(h.quota.Limit(), 10))
+			w.Header().Set("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+			if !ok {
+				w.Header().Set("Retry-After", seconds(ratelimit.UntilReset(now)))
+				h.writeProblem(w, r, http.StatusTooManyRequests, CodeQuotaExceeded, "daily quota exceeded")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// charge cobra una conversion a key en el limiter y la cuota, para los canales donde un request
+// convierte varias veces, como los batches y gRPC. Si el cliente no tiene tokens retorna el codigo
+// del error y cuanto esperar.
+func (h *HTTP) charge(key string, now time.Time) (string, time.Duration) {
+	if h.limiter != nil {
+		if d := h.limiter.Allow(key, now); !d.Allowed {
+			return CodeRateLimited, d.Reset
+		}
+	}
+	if h.quota != nil {
+		if _, ok := h.quota.Allow(key, now); !ok {
+			return CodeQuotaExceeded, ratelimit.UntilReset(now)
+		}
+	}
+	return "", 0
+}
+
+// seconds redondea hacia arriba, un Retry-After de 0 haria que el cliente reintente de inmediato.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,170 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/apikey"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	quota, err := ratelimit.OpenQuota(filepath.Join(t.TempDir(), "quota.json"), 3, time.Now())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := newTestHTTP(&strings.Builder{})
+	h.limiter = ratelimit.NewLimiter(0.001, 2)
+	h.quota = quota
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		expectedCode int
+		expectedErr  string
+		remaining    string
+	}{
+		{"first request", "192.0.2.1:1000", http.StatusOK, "", "1"},
+		{"second request", "192.0.2.1:1001", http.StatusOK, "", "0"},
+		{"over burst", "192.0.2.1:1002", http.StatusTooManyRequests, CodeRateLimited, "0"},
+		{"other client", "192.0.2.2:1000", http.StatusOK, "", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			req.RemoteAddr = tt.remoteAddr
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+				t.Errorf("Expected RateLimit-Limit 2, got %q", got)
+			}
+			if got := rec.Header().Get("RateLimit-Remaining"); got != tt.remaining {
+				t.Errorf("Expected RateLimit-Remaining %s, got %q", tt.remaining, got)
+			}
+			if tt.expectedErr == "" {
+				return
+			}
+			if rec.Header().Get("Retry-After") == "" {
+				t.Errorf("Expected Retry-After header")
+			}
+			var body map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("Expected problem body: %v", err)
+			}
+			if body["code"] != tt.expectedErr {
+				t.Errorf("Expected code %s, got %v", tt.expectedErr, body["code"])
+			}
+		})
+	}
+
+	// con el limiter holgado se agota la cuota diaria de 192.0.2.2
+	h.limiter = ratelimit.NewLimiter(1000, 1000)
+	var rec *httptest.ResponseRecorder
+	for range 3 {
+		req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+		req.RemoteAddr = "192.0.2.2:1000"
+		rec = httptest.NewRecorder()
+		h.Handler().ServeHTTP(rec, req)
+	}
+	if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), CodeQuotaExceeded) {
+		t.Errorf("Expected quota exceeded, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Quota-Remaining"); got != "0" {
+		t.Errorf("Expected X-Quota-Remaining 0, got %q", got)
+	}
+}
+
+// las credenciales invalidas se rechazan antes de rateLimit, que limita por credencial, asi que se
+// limitan por IP en authenticate
+func TestAuthFailures(t *testing.T) {
+	keys, err := apikey.Open(filepath.Join(t.TempDir(), "keys.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reader, _, err := keys.Create("lector", []string{ScopeConvertRead}, time.Time{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.keys = keys
+	h.authFailures = ratelimit.NewLimiter(0.001, 3)
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	get := func(remoteAddr string, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/patente/1", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(apiKeyHeader, key)
+		rec := httptest.NewRecorder()
+		h.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	for i := range 3 {
+		if rec := get("192.0.2.1:1000", "pk_nope_nope"); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected attempt %d to get 401, got %d", i, rec.Code)
+		}
+	}
+	// agotados los intentos ni una key valida se revisa
+	for _, key := range []string{"pk_nope_nope", reader} {
+		rec := get("192.0.2.1:1000", key)
+		if rec.Code != http.StatusTooManyRequests || !strings.Contains(rec.Body.String(), CodeRateLimited) {
+			t.Fatalf("Expected 429, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Retry-After") == "" {
+			t.Errorf("Expected Retry-After header")
+		}
+	}
+	if rec := get("192.0.2.2:1000", reader); rec.Code != http.StatusOK {
+		t.Errorf("Expected other IPs unaffected, got %d", rec.Code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := parseTrustedProxies("10.0.0.0/8, 192.0.2.10")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	h := &HTTP{trustedProxies: proxies}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		expected   string
+	}{
+		{"direct client", "203.0.113.5:1000", "", "203.0.113.5"},
+		{"untrusted peer ignores header", "203.0.113.5:1000", "198.51.100.1", "203.0.113.5"},
+		{"trusted proxy", "10.0.0.1:1000", "198.51.100.1", "198.51.100.1"},
+		{"skips trusted hops", "10.0.0.1:1000", "198.51.100.1, 192.0.2.10, 10.1.1.1", "198.51.100.1"},
+		{"spoofed left entry", "10.0.0.1:1000", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"trusted proxy without header", "10.0.0.1:1000", "", "10.0.0.1"},
+		{"invalid entry", "10.0.0.1:1000", "nope, 10.0.0.2", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := h.clientIP(req); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
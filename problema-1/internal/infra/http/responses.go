@@ -0,0 +1,35 @@
+package http_adapter
+
+import "encoding/xml"
+
+// field es un campo de una respuesta, CSV, texto y CBOR necesitan los campos en orden.
+type field struct {
+	name  string
+	value any
+}
+
+// response es una respuesta exitosa que se puede codificar en cualquiera de los formatos de
+// encoders. Los tags json y xml los usan los encoders de la libreria estandar.
+type response interface {
+	fields() []field
+}
+
+// PatentResponse es la respuesta de GET /patente/{id}.
+type PatentResponse struct {
+	XMLName xml.Name `json:"-" xml:"conversion"`
+	Patente string   `json:"patente" xml:"patente"`
+}
+
+func (p PatentResponse) fields() []field {
+	return []field{{"patente", p.Patente}}
+}
+
+// IDResponse es la respuesta de GET /id/{patente}.
+type IDResponse struct {
+	XMLName xml.Name `json:"-" xml:"conversion"`
+	ID      uint     `json:"id" xml:"id"`
+}
+
+func (i IDResponse) fields() []field {
+	return []field{{"id", i.ID}}
+}
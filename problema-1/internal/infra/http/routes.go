@@ -1,7 +1,230 @@
 package http_adapter
 
+import (
+	"net/http"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+// route describe una ruta de la api. SetRoutes la registra y openAPI la documenta a partir de la
+// misma definicion, asi la especificacion no se puede desactualizar.
+//
+// Las rutas de la api llevan la version en el path (GET /v1/...), una nueva version se agrega como
+// otra ruta con su propio handler y tipo de respuesta (GET /v2/...) y ambas se sirven a la vez. Las
+// de infraestructura como /healthcheck no tienen version.
+type route struct {
+	pattern string
+	// legacy tambien sirve la ruta sin version, deprecada, para los clientes anteriores a /v1. Solo
+	// una version puede tener el path sin version.
+	legacy  bool
+	handler http.HandlerFunc
+	// scope es el scope que exige la ruta, vacio para rutas anonimas
+	scope   string
+	summary string
+	params  []param
+	// response es la respuesta de las rutas que negocian formato, las demas responden contentType
+	response    response
+	contentType string
+	// requestBody es el content type del body que recibe la ruta, vacio si no recibe body
+	requestBody string
+	// websocket indica que la ruta responde 101 y sigue por WebSocket
+	websocket bool
+	// errors son los status de error que responde el handler, ademas de los de los middlewares
+	errors []int
+}
+
+// param es un parametro del path de la ruta.
+type param struct {
+	name        string
+	description string
+	schema      map[string]any
+	example     any
+}
+
+func (h *HTTP) routes() []route {
+	routes := []route{
+		{
+			pattern: "GET /v1/patente/{id}",
+			legacy:  true,
+			handler: h.getPatentByID,
+			scope:   ScopeConvertRead,
+			summary: "Convierte un id en su patente",
+			params: []param{{
+				name:        "id",
+				description: "Id de la patente, desde 1",
+				schema:      map[string]any{"type": "integer", "minimum": 1, "maximum": app.MaxID},
+				example:     1,
+			}},
+			response: PatentResponse{},
+			errors:   []int{http.StatusBadRequest},
+		},
+		{
+			pattern: "GET /v1/id/{patente}",
+			legacy:  true,
+			handler: h.getIDByPatent,
+			scope:   ScopeConvertRead,
+			summary: "Convierte una patente en su id",
+			params: []param{{
+				name:        "patente",
+				description: "Patente de 4 letras y 3 digitos",
+				schema:      map[string]any{"type": "string", "pattern": "^[A-Z]{4}[0-9]{3}$"},
+				example:     "AAAA000",
+			}},
+			response: IDResponse{},
+			errors:   []int{http.StatusBadRequest},
+		},
+		{
+			pattern:   "GET /v1/ws",
+			handler:   h.convertWS,
+			scope:     ScopeConvertRead,
+			summary:   "Canal WebSocket para validar y convertir mientras se escribe",
+			websocket: true,
+			errors: []int{
+				http.StatusBadRequest,
+				http.StatusUpgradeRequired,
+				http.StatusServiceUnavailable,
+			},
+		},
+		{
+			pattern:     "POST /rpc",
+			handler:     h.rpc,
+			scope:       ScopeConvertRead,
+			summary:     "Conversiones por JSON-RPC 2.0, los metodos se describen con rpc.discover",
+			contentType: "application/json",
+			requestBody: "application/json",
+			errors:      []int{http.StatusRequestEntityTooLarge},
+		},
+		{
+			pattern:     "POST /graphql",
+			handler:     h.graphql,
+			scope:       ScopeConvertRead,
+			summary:     "Queries GraphQL sobre las conversiones, el schema esta en /graphql/schema",
+			contentType: "application/json",
+			requestBody: "application/json",
+			errors:      []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge},
+		},
+		{
+			pattern:     "GET /graphql",
+			handler:     h.graphql,
+			scope:       ScopeConvertRead,
+			summary:     "Queries GraphQL en los parametros query, variables y operationName",
+			contentType: "application/json",
+			errors:      []int{http.StatusBadRequest},
+		},
+		{
+			pattern:     "GET /graphql/schema",
+			handler:     h.graphqlSDL,
+			summary:     "Schema GraphQL en SDL",
+			contentType: "text/plain",
+		},
+		{
+			pattern:     "GET /events/stream",
+			handler:     h.streamEvents,
+			scope:       ScopeAdmin,
+			summary:     "Eventos de conversiones y errores por Server-Sent Events, filtrables por route y type",
+			contentType: "text/event-stream",
+			errors:      []int{http.StatusBadRequest},
+		},
+		{
+			pattern:     "GET /diagnostics",
+			handler:     h.diagnostics,
+			scope:       ScopeAdmin,
+			summary:     "Version, configuracion sin credenciales y estado del servidor",
+			contentType: "application/json",
+		},
+		{
+			pattern:     "GET /healthcheck",
+			handler:     h.healthCheck,
+			summary:     "Indica si el servidor esta respondiendo",
+			contentType: "text/plain",
+		},
+		{
+			pattern:     "GET /openapi.json",
+			handler:     h.openAPI,
+			summary:     "Especificacion OpenAPI 3 de la api",
+			contentType: "application/json",
+		},
+		{
+			pattern:     "GET /docs/",
+			handler:     h.docs,
+			summary:     "Documentacion interactiva de la api",
+			contentType: "text/html",
+		},
+		{
+			pattern:     "GET /ui/",
+			handler:     h.ui,
+			summary:     "Interfaz web para convertir y revisar el estado del servidor",
+			contentType: "text/html",
+		},
+	}
+	if h.metrics != nil && h.metricsAddr == "" {
+		routes = append(routes, route{
+			pattern:     "GET /metrics",
+			handler:     h.metrics.registry.Handler().ServeHTTP,
+			scope:       ScopeAdmin,
+			summary:     "Metricas en formato de texto de Prometheus",
+			contentType: "text/plain",
+		})
+	}
+	return routes
+}
+
 func (h *HTTP) SetRoutes() {
-	h.mux.HandleFunc("GET /patente/{id}", h.getPatentByID)
-	h.mux.HandleFunc("GET /id/{patente}", h.getIDByPatent)
-	h.mux.HandleFunc("GET /healthcheck", h.healthCheck)
+	h.graphqlSchema = h.buildGraphQLSchema()
+	h.routeVersions = map[string]string{}
+	h.routeAliases = map[string]string{}
+	h.conversionRoutes = map[string]bool{}
+	for _, rt := range h.routes() {
+		var handler http.Handler = rt.handler
+		if rt.scope != "" {
+			handler = h.authorize(rt.scope, rt.handler)
+		}
+		h.mux.Handle(rt.pattern, handler)
+		if rt.scope == ScopeConvertRead {
+			h.conversionRoutes[rt.pattern] = true
+			if rt.legacy {
+				h.conversionRoutes[legacyPattern(rt.pattern)] = true
+			}
+		}
+
+		version := routeVersion(rt.pattern)
+		if version == "" {
+			continue
+		}
+		h.routeVersions[rt.pattern] = version
+		if rt.legacy {
+			h.mux.Handle(legacyPattern(rt.pattern), h.deprecated(version, handler))
+			h.routeVersions[legacyPattern(rt.pattern)] = "legacy"
+			h.routeAliases[rt.pattern] = legacyPattern(rt.pattern)
+			h.routeAliases[legacyPattern(rt.pattern)] = rt.pattern
+		}
+	}
+}
+
+// routePatterns retorna los patrones registrados, incluidos los sin version de las rutas deprecadas.
+func (h *HTTP) routePatterns() []string {
+	var patterns []string
+	for _, rt := range h.routes() {
+		patterns = append(patterns, rt.pattern)
+		if rt.legacy {
+			patterns = append(patterns, legacyPattern(rt.pattern))
+		}
+	}
+	return patterns
+}
+
+// SetMiddlewares define la cadena de middlewares, el primero es el mas externo.
+func (h *HTTP) SetMiddlewares() {
+	h.Use(
+		h.accessLog,
+		h.securityHeaders,
+		h.cors,
+		h.compress,
+		h.instrument,
+		h.recoverer,
+		h.decompress,
+		h.limitBody,
+		h.authenticate,
+		h.rateLimit,
+	)
 }
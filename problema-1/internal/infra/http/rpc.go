@@ -0,0 +1,343 @@
+package http_adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+// Codigos de error de JSON-RPC 2.0, los errores de dominio usan rpcAppError con el codigo de app en
+// data.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	rpcAppError       = -32000
+)
+
+// rpcMaxBatch limita los llamados de un batch, el limite del body no alcanza porque cada llamado es
+// muy chico.
+const rpcMaxBatch = 100
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	// ID es nil si el request no lo tiene, en ese caso es una notificacion y no se responde
+	ID json.RawMessage `json:"id"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// rpcMethod es un metodo de /rpc, rpc.discover lo documenta a partir de la misma definicion.
+type rpcMethod struct {
+	name    string
+	summary string
+	params  []param
+	result  response
+	call    func(ctx context.Context, args map[string]json.RawMessage) (any, error)
+}
+
+func (h *HTTP) rpcMethods() []rpcMethod {
+	return []rpcMethod{
+		{
+			name:    "patentFromID",
+			summary: "Convierte un id en su patente",
+			params: []param{{
+				name:        "id",
+				description: "Id de la patente, desde 1",
+				schema:      map[string]any{"type": "integer", "minimum": 1, "maximum": app.MaxID},
+				example:     1,
+			}},
+			result: PatentResponse{},
+			call: func(ctx context.Context, args map[string]json.RawMessage) (any, error) {
+				var id uint
+				if err := rpcArg(args, "id", &id); err != nil {
+					return nil, err
+				}
+				patente, err := h.app.PatentFromID(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				return PatentResponse{Patente: patente}, nil
+			},
+		},
+		{
+			name:    "idFromPatent",
+			summary: "Convierte una patente en su id",
+			params: []param{{
+				name:        "patente",
+				description: "Patente de 4 letras y 3 digitos",
+				schema:      map[string]any{"type": "string", "pattern": "^[A-Z]{4}[0-9]{3}$"},
+				example:     "AAAA000",
+			}},
+			result: IDResponse{},
+			call: func(ctx context.Context, args map[string]json.RawMessage) (any, error) {
+				var patente string
+				if err := rpcArg(args, "patente", &patente); err != nil {
+					return nil, err
+				}
+				id, err := h.app.IDFromPatent(ctx, patente)
+				if err != nil {
+					return nil, err
+				}
+				return IDResponse{ID: id}, nil
+			},
+		},
+	}
+}
+
+// rpcArg decodifica el parametro name, que es obligatorio.
+func rpcArg(args map[string]json.RawMessage, name string, v any) error {
+	raw, ok := args[name]
+	if !ok {
+		return &rpcError{Code: rpcInvalidParams, Message: "missing param " + name}
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("param %s must be a %s", name, reflect.TypeOf(v).Elem())}
+	}
+	return nil
+}
+
+// rpcArgs acepta los parametros por posicion o por nombre y los retorna por nombre.
+func rpcArgs(params json.RawMessage, names []param) (map[string]json.RawMessage, error) {
+	args := map[string]json.RawMessage{}
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return args, nil
+	}
+	invalid := &rpcError{Code: rpcInvalidParams, Message: "params must be an array or an object"}
+	switch params[0] {
+	case '[':
+		var positional []json.RawMessage
+		if err := json.Unmarshal(params, &positional); err != nil {
+			return nil, invalid
+		}
+		if len(positional) > len(names) {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("expected at most %d params", len(names))}
+		}
+		for i, value := range positional {
+			args[names[i].name] = value
+		}
+	case '{':
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, invalid
+		}
+		for name := range args {
+			known := false
+			for _, p := range names {
+				known = known || p.name == name
+			}
+			if !known {
+				return nil, &rpcError{Code: rpcInvalidParams, Message: "unknown param " + name}
+			}
+		}
+	default:
+		return nil, invalid
+	}
+	return args, nil
+}
+
+// rpc implementa JSON-RPC 2.0 sobre POST /rpc. Los llamados de un batch se ejecutan en orden y si
+// todos son notificaciones responde 204 sin body. Cada llamado cuesta un token, el primero lo cobra
+// el middleware rateLimit con el request y los demas se cobran al ejecutarlos.
+func (h *HTTP) rpc(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.writeAppError(w, r, err)
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		h.writeRPC(w, rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: rpcParseError, Message: "parse error"}})
+		return
+	}
+
+	if body[0] != '[' {
+		resp, ok := h.rpcCall(r.Context(), body, nil)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.writeRPC(w, resp)
+		return
+	}
+
+	var calls []json.RawMessage
+	json.Unmarshal(body, &calls)
+	if len(calls) == 0 || len(calls) > rpcMaxBatch {
+		h.writeRPC(w, rpcResponse{JSONRPC: "2.0", Error: &rpcError{
+			Code:    rpcInvalidRequest,
+			Message: fmt.Sprintf("batch must have between 1 and %d calls", rpcMaxBatch),
+		}})
+		return
+	}
+	key := h.clientKey(r)
+	charge := func() error {
+		code, retryAfter := h.charge(key, time.Now())
+		if code == "" {
+			return nil
+		}
+		return &rpcError{
+			Code:    rpcAppError,
+			Message: message(r.Context(), code, "too many requests"),
+			Data:    map[string]any{"code": code, "retry_after": int(math.Ceil(retryAfter.Seconds()))},
+		}
+	}
+	responses := []rpcResponse{}
+	for i, call := range calls {
+		var callCharge func() error
+		if i > 0 {
+			callCharge = charge
+		}
+		if resp, ok := h.rpcCall(r.Context(), call, callCharge); ok {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	h.writeRPC(w, responses)
+}
+
+// rpcCall ejecuta un llamado, retorna false si es una notificacion y no hay que responder. Si charge
+// no es nil se llama antes de ejecutar un llamado valido y su error reemplaza al resultado.
+func (h *HTTP) rpcCall(ctx context.Context, raw json.RawMessage, charge func() error) (rpcResponse, bool) {
+	resp := rpcResponse{JSONRPC: "2.0"}
+	var req rpcRequest
+	if err := json.Unmarshal(raw, &req); err != nil || req.JSONRPC != "2.0" || req.Method == "" || !validRPCID(req.ID) {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		return resp, true
+	}
+	resp.ID = req.ID
+
+	var result any
+	var err error
+	if charge != nil {
+		err = charge()
+	}
+	if err == nil {
+		result, err = h.rpcDispatch(ctx, req)
+	}
+	if req.ID == nil {
+		return resp, false
+	}
+	if err != nil {
+		resp.Error = h.rpcErrorFrom(ctx, err)
+		return resp, true
+	}
+	resp.Result = result
+	return resp, true
+}
+
+func (h *HTTP) rpcDispatch(ctx context.Context, req rpcRequest) (any, error) {
+	if req.Method == "rpc.discover" {
+		return h.rpcDiscover(), nil
+	}
+	for _, m := range h.rpcMethods() {
+		if m.name != req.Method {
+			continue
+		}
+		args, err := rpcArgs(req.Params, m.params)
+		if err != nil {
+			return nil, err
+		}
+		return m.call(ctx, args)
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// rpcErrorFrom convierte los errores de los metodos, igual que writeAppError un error sin codigo
+// de dominio es interno y su texto no se expone.
+func (h *HTTP) rpcErrorFrom(ctx context.Context, err error) *rpcError {
+	if rpcErr, ok := err.(*rpcError); ok {
+		return rpcErr
+	}
+	code := app.ErrorCode(err)
+	if code == "" {
+		h.logger.ErrorContext(ctx, "Unexpected app error", "error", err.Error())
+		return &rpcError{Code: rpcInternalError, Message: "internal error"}
+	}
+	return &rpcError{Code: rpcAppError, Message: appMessage(ctx, err), Data: map[string]any{"code": code}}
+}
+
+// validRPCID acepta ids string, numero o null, y la ausencia de id de las notificaciones.
+func validRPCID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	return false
+}
+
+// rpcDiscover describe los metodos en formato OpenRPC.
+func (h *HTTP) rpcDiscover() map[string]any {
+	var methods []any
+	for _, m := range h.rpcMethods() {
+		var params []any
+		for _, p := range m.params {
+			params = append(params, map[string]any{
+				"name":        p.name,
+				"description": p.description,
+				"required":    true,
+				"schema":      p.schema,
+			})
+		}
+		methods = append(methods, map[string]any{
+			"name":           m.name,
+			"summary":        m.summary,
+			"paramStructure": "either",
+			"params":         params,
+			"result": map[string]any{
+				"name":   reflect.TypeOf(m.result).Name(),
+				"schema": schemaOf(reflect.TypeOf(m.result)),
+			},
+			"errors": []any{
+				map[string]any{"code": rpcAppError, "message": "conversion error, data.code has the reason"},
+			},
+		})
+	}
+	return map[string]any{
+		"openrpc": "1.2.6",
+		"info": map[string]any{
+			"title":       "Patentes",
+			"description": "Conversion entre patentes y sus ids",
+			"version":     version,
+		},
+		"methods": methods,
+	}
+}
+
+func (h *HTTP) writeRPC(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
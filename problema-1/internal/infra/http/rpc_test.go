@@ -0,0 +1,235 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
+)
+
+func TestRPC(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expected     string
+	}{
+		{
+			"positional params",
+			`{"jsonrpc": "2.0", "method": "patentFromID", "params": [1], "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "result": {"patente": "AAAA000"}, "id": 1}`,
+		},
+		{
+			"named params",
+			`{"jsonrpc": "2.0", "method": "idFromPatent", "params": {"patente": "AAAA001"}, "id": "a"}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "result": {"id": 2}, "id": "a"}`,
+		},
+		{
+			"null id is answered",
+			`{"jsonrpc": "2.0", "method": "patentFromID", "params": [1], "id": null}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "result": {"patente": "AAAA000"}, "id": null}`,
+		},
+		{
+			"notification",
+			`{"jsonrpc": "2.0", "method": "patentFromID", "params": [1]}`,
+			http.StatusNoContent,
+			``,
+		},
+		{
+			"domain error",
+			`{"jsonrpc": "2.0", "method": "idFromPatent", "params": ["AAA"], "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32000, "message": "the plate must have 4 letters and 3 digits, like AAAA000", "data": {"code": "bad_format"}}, "id": 1}`,
+		},
+		{
+			"parse error",
+			`{"jsonrpc": "2.0", "method"`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32700, "message": "parse error"}, "id": null}`,
+		},
+		{
+			"invalid request",
+			`{"jsonrpc": "1.0", "method": "patentFromID", "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "invalid request"}, "id": null}`,
+		},
+		{
+			"method not found",
+			`{"jsonrpc": "2.0", "method": "nope", "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32601, "message": "method not found: nope"}, "id": 1}`,
+		},
+		{
+			"missing param",
+			`{"jsonrpc": "2.0", "method": "patentFromID", "params": {}, "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32602, "message": "missing param id"}, "id": 1}`,
+		},
+		{
+			"wrong param type",
+			`{"jsonrpc": "2.0", "method": "patentFromID", "params": ["1"], "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32602, "message": "param id must be a uint"}, "id": 1}`,
+		},
+		{
+			"unknown param",
+			`{"jsonrpc": "2.0", "method": "patentFromID", "params": {"id": 1, "x": 2}, "id": 1}`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32602, "message": "unknown param x"}, "id": 1}`,
+		},
+		{
+			"batch",
+			`[
+				{"jsonrpc": "2.0", "method": "patentFromID", "params": [1], "id": 1},
+				{"jsonrpc": "2.0", "method": "patentFromID", "params": [2]},
+				1,
+				{"jsonrpc": "2.0", "method": "idFromPatent", "params": ["AAAA001"], "id": 2}
+			]`,
+			http.StatusOK,
+			`[
+				{"jsonrpc": "2.0", "result": {"patente": "AAAA000"}, "id": 1},
+				{"jsonrpc": "2.0", "error": {"code": -32600, "message": "invalid request"}, "id": null},
+				{"jsonrpc": "2.0", "result": {"id": 2}, "id": 2}
+			]`,
+		},
+		{
+			"batch of notifications",
+			`[{"jsonrpc": "2.0", "method": "patentFromID", "params": [1]}]`,
+			http.StatusNoContent,
+			``,
+		},
+		{
+			"empty batch",
+			`[]`,
+			http.StatusOK,
+			`{"jsonrpc": "2.0", "error": {"code": -32600, "message": "batch must have between 1 and 100 calls"}, "id": null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedCode, rec.Code, rec.Body.String())
+			}
+			if tt.expected == "" {
+				if rec.Body.Len() != 0 {
+					t.Errorf("Expected empty body, got %s", rec.Body.String())
+				}
+				return
+			}
+			var got, expected any
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to decode body: %v", err)
+			}
+			json.Unmarshal([]byte(tt.expected), &expected)
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Expected %s, got %s", tt.expected, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRPCDiscover(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.SetRoutes()
+
+	body := `{"jsonrpc": "2.0", "method": "rpc.discover", "id": 1}`
+	rec := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+
+	var resp struct {
+		Result struct {
+			OpenRPC string `json:"openrpc"`
+			Methods []struct {
+				Name   string `json:"name"`
+				Params []struct {
+					Name string `json:"name"`
+				} `json:"params"`
+			} `json:"methods"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	methods := map[string]string{}
+	for _, m := range resp.Result.Methods {
+		if len(m.Params) == 1 {
+			methods[m.Name] = m.Params[0].Name
+		}
+	}
+	expected := map[string]string{"patentFromID": "id", "idFromPatent": "patente"}
+	if !reflect.DeepEqual(methods, expected) {
+		t.Errorf("Expected methods %v, got %v", expected, methods)
+	}
+	if resp.Result.OpenRPC == "" {
+		t.Errorf("Expected openrpc version in %s", rec.Body.String())
+	}
+}
+
+func TestRPCBatchCharge(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	// tres tokens, el request paga el primer llamado y el batch los otros dos
+	h.limiter = ratelimit.NewLimiter(0.001, 3)
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	body := `[
+		{"jsonrpc": "2.0", "method": "patentFromID", "params": [1], "id": 1},
+		{"jsonrpc": "2.0", "method": "patentFromID", "params": [2], "id": 2},
+		{"jsonrpc": "2.0", "method": "patentFromID", "params": [3]},
+		{"jsonrpc": "2.0", "method": "patentFromID", "params": [4], "id": 4}
+	]`
+	rec := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+
+	var responses []struct {
+		ID    int
+		Error *struct {
+			Code int
+			Data struct {
+				Code       string
+				RetryAfter int `json:"retry_after"`
+			}
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil || len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d: %s", rec.Code, rec.Body.String())
+	}
+	for _, resp := range responses[:2] {
+		if resp.Error != nil {
+			t.Errorf("Expected call %d to succeed, got %+v", resp.ID, resp.Error)
+		}
+	}
+	if last := responses[2]; last.ID != 4 || last.Error == nil || last.Error.Code != rpcAppError ||
+		last.Error.Data.Code != CodeRateLimited || last.Error.Data.RetryAfter < 1 {
+		t.Errorf("Expected call 4 to be rate limited, got %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body)))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 for the next batch, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,87 @@
+package http_adapter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/certs"
+	"github.com/docopt/docopt-go"
+)
+
+// tlsConfig arma la configuracion TLS con --tls-cert/--tls-key, que se recargan al cambiar, o con un
+// certificado autofirmado en memoria si se pasa --dev-tls. Con --tls-client-ca ademas exige
+// certificados de cliente firmados por esa CA. Retorna nil si el servidor va en texto plano.
+func tlsConfig(opts docopt.Opts, host string, logger *slog.Logger) (*tls.Config, error) {
+	certFile, _ := opts.String("--tls-cert")
+	keyFile, _ := opts.String("--tls-key")
+	clientCA, _ := opts.String("--tls-client-ca")
+	devTLS, _ := opts.Bool("--dev-tls")
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("tls: --tls-cert and --tls-key must be used together")
+	}
+	if certFile != "" && devTLS {
+		return nil, errors.New("tls: --dev-tls cannot be used with --tls-cert")
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	switch {
+	case certFile != "":
+		reloader, err := certs.NewReloader(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		reloader.OnError = func(err error) {
+			logger.Error("Failed to reload certificate", "error", err.Error())
+		}
+		config.GetCertificate = reloader.GetCertificate
+	case devTLS:
+		hosts := []string{"localhost", "127.0.0.1", "::1"}
+		if host != "0.0.0.0" && host != "::" {
+			hosts = append(hosts, host)
+		}
+		cert, err := certs.SelfSigned(hosts, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+		logger.Warn("Serving with a self-signed development certificate", "sha256", certs.Fingerprint(cert))
+	default:
+		if clientCA != "" {
+			return nil, errors.New("tls: --tls-client-ca requires --tls-cert or --dev-tls")
+		}
+		return nil, nil
+	}
+
+	if clientCA != "" {
+		pool, err := certs.LoadCAPool(clientCA)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// clientCertPrincipal retorna el principal del certificado de cliente verificado, el id es el CN y
+// los scopes son las OU del certificado.
+func clientCertPrincipal(r *http.Request) *Principal {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return certPrincipal(r.TLS.VerifiedChains[0][0])
+}
+
+func certPrincipal(leaf *x509.Certificate) *Principal {
+	return &Principal{
+		Method: "mtls",
+		ID:     leaf.Subject.CommonName,
+		Label:  leaf.Subject.String(),
+		Scopes: leaf.Subject.OrganizationalUnit,
+	}
+}
@@ -0,0 +1,198 @@
+package http_adapter
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/docopt/docopt-go"
+)
+
+// newTestCA crea una CA y un certificado de cliente firmado por ella con el CN y las OU dadas.
+func newTestCA(t *testing.T, cn string, ou []string) (*x509.Certificate, tls.Certificate) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: cn, OrganizationalUnit: ou},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, ca, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return ca, tls.Certificate{Certificate: [][]byte{clientDER}, PrivateKey: clientKey}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca, clientCert := newTestCA(t, "kiosko-1", []string{ScopeConvertRead})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	logs := &strings.Builder{}
+	config, err := tlsConfig(docopt.Opts{
+		"--tls-cert":      nil,
+		"--tls-key":       nil,
+		"--tls-client-ca": caFile,
+		"--dev-tls":       true,
+	}, "127.0.0.1", newTestHTTP(logs).logger)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	h := newTestHTTP(logs)
+	h.app = &app.App{}
+	h.mtls = true
+	h.metrics = newHTTPMetrics(func() map[string]uint64 { return nil })
+	h.mux.HandleFunc("GET /whoami", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(PrincipalFrom(r.Context()))
+	})
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	server := httptest.NewUnstartedServer(h.Handler())
+	server.TLS = config
+	// el handshake sin certificado se loguea como error del servidor
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(config.Certificates[0].Leaf)
+	client := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: certs,
+		}}}
+	}
+
+	if _, err := client().Get(server.URL + "/healthcheck"); err == nil {
+		t.Errorf("Expected handshake to fail without a client certificate")
+	}
+
+	resp, err := client(clientCert).Get(server.URL + "/whoami")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	var principal Principal
+	if err := json.NewDecoder(resp.Body).Decode(&principal); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if principal.Method != "mtls" || principal.ID != "kiosko-1" {
+		t.Errorf("Expected mtls principal kiosko-1, got %+v", principal)
+	}
+
+	// las OU del certificado son sus scopes
+	resp, err = client(clientCert).Get(server.URL + "/patente/1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	resp, err = client(clientCert).Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status 403 without admin scope, got %d", resp.StatusCode)
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts docopt.Opts
+	}{
+		{"cert without key", docopt.Opts{"--tls-cert": "cert.pem", "--tls-key": nil, "--tls-client-ca": nil, "--dev-tls": false}},
+		{"cert with dev tls", docopt.Opts{"--tls-cert": "cert.pem", "--tls-key": "key.pem", "--tls-client-ca": nil, "--dev-tls": true}},
+		{"client ca without tls", docopt.Opts{"--tls-cert": nil, "--tls-key": nil, "--tls-client-ca": "ca.pem", "--dev-tls": false}},
+		{"missing files", docopt.Opts{"--tls-cert": "nope.pem", "--tls-key": "nope.pem", "--tls-client-ca": nil, "--dev-tls": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tlsConfig(tt.opts, "127.0.0.1", newTestHTTP(&strings.Builder{}).logger); err == nil {
+				t.Errorf("Expected error")
+			}
+		})
+	}
+}
+
+func TestDevTLSServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	baseURL := setupTestServer(t, ctx, "--dev-tls")
+	baseURL = strings.Replace(baseURL, "http://", "https://", 1)
+
+	// el certificado es autofirmado, el cliente no lo puede verificar
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	var resp *http.Response
+	var err error
+	for range 50 {
+		resp, err = client.Get(baseURL + "/healthcheck")
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Error al hacer la solicitud: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Se esperaba status 200, se obtuvo %d", resp.StatusCode)
+	}
+	if resp.TLS == nil {
+		t.Errorf("Se esperaba una conexion TLS")
+	}
+}
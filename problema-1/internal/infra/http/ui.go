@@ -0,0 +1,112 @@
+package http_adapter
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docopt/docopt-go"
+)
+
+//go:embed ui
+var uiFS embed.FS
+
+// redacted reemplaza los valores de las opciones con credenciales en /diagnostics.
+const redacted = "[redacted]"
+
+// sensitiveOptions son partes de nombres de opciones cuyo valor no se muestra, aunque sean paths
+// dicen donde estan las credenciales del servidor.
+var sensitiveOptions = []string{"key", "secret", "token", "password", "jwt", "tls", "quota-file"}
+
+// ui sirve la interfaz web embebida para soporte. Como docs, no usa recursos externos y necesita una
+// CSP que permita sus propios scripts y estilos.
+func (h *HTTP) ui(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+	sub, _ := fs.Sub(uiFS, "ui")
+	http.StripPrefix("/ui/", http.FileServerFS(sub)).ServeHTTP(w, r)
+}
+
+// redactConfig arma la configuracion que muestra /diagnostics a partir de las opciones de la linea
+// de comandos, sin los valores de las opciones con credenciales.
+func redactConfig(opts docopt.Opts) map[string]string {
+	config := map[string]string{}
+	for name, value := range opts {
+		if !strings.HasPrefix(name, "--") || name == "--help" || name == "--version" || value == nil {
+			continue
+		}
+		text, ok := value.(string)
+		if !ok {
+			b, _ := value.(bool)
+			if !b {
+				continue
+			}
+			text = "true"
+		}
+		for _, sensitive := range sensitiveOptions {
+			if strings.Contains(name, sensitive) {
+				text = redacted
+				break
+			}
+		}
+		config[strings.TrimPrefix(name, "--")] = text
+	}
+	return config
+}
+
+// diagnostics es la respuesta de GET /diagnostics.
+type diagnostics struct {
+	Version   string            `json:"version"`
+	GoVersion string            `json:"go_version"`
+	StartedAt time.Time         `json:"started_at"`
+	Uptime    string            `json:"uptime"`
+	Health    map[string]string `json:"health"`
+	Config    map[string]string `json:"config"`
+	Routes    []string          `json:"routes"`
+}
+
+// diagnostics responde la version, la configuracion sin credenciales y el estado del servidor. El
+// estado de app se comprueba con una conversion de ida y vuelta.
+func (h *HTTP) diagnostics(w http.ResponseWriter, r *http.Request) {
+	health := map[string]string{"http": "ok", "app": "ok"}
+	patente, err := h.app.PatentFromID(r.Context(), 1)
+	if err == nil {
+		var id uint
+		id, err = h.app.IDFromPatent(r.Context(), patente)
+		if err == nil && id != 1 {
+			health["app"] = "round trip returned a different id"
+		}
+	}
+	if err != nil {
+		health["app"] = err.Error()
+	}
+	if h.ws != nil {
+		health["websocket_connections"] = strconv.Itoa(len(h.ws.slots))
+	}
+
+	var routes []string
+	for _, rt := range h.routes() {
+		routes = append(routes, rt.pattern)
+	}
+	sort.Strings(routes)
+
+	d := diagnostics{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		StartedAt: h.started.UTC(),
+		Uptime:    time.Since(h.started).Round(time.Second).String(),
+		Health:    health,
+		Config:    h.config,
+		Routes:    routes,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(d)
+}
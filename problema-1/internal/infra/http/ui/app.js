@@ -0,0 +1,127 @@
+// Pagina de conversiones: formularios de id a patente, patente a id y lote, con la patente dibujada
+// como una placa. El lote usa un batch de JSON-RPC para convertir todo en un solo request.
+"use strict";
+
+const svgNS = "http://www.w3.org/2000/svg";
+// maxBatch es el limite de un batch de /rpc
+const maxBatch = 100;
+
+// plate dibuja la patente como una placa en SVG, sin imagenes externas.
+function plate(patente) {
+  const svg = document.createElementNS(svgNS, "svg");
+  svg.setAttribute("viewBox", "0 0 220 60");
+  svg.setAttribute("class", "plate");
+  svg.setAttribute("role", "img");
+  svg.setAttribute("aria-label", "Patente " + patente);
+
+  const border = document.createElementNS(svgNS, "rect");
+  border.setAttribute("x", "2");
+  border.setAttribute("y", "2");
+  border.setAttribute("width", "216");
+  border.setAttribute("height", "56");
+  border.setAttribute("rx", "6");
+  border.setAttribute("fill", "#fff");
+  border.setAttribute("stroke", "#1f2328");
+  border.setAttribute("stroke-width", "3");
+
+  const text = document.createElementNS(svgNS, "text");
+  text.setAttribute("x", "110");
+  text.setAttribute("y", "42");
+  text.setAttribute("text-anchor", "middle");
+  text.setAttribute("font-family", "monospace");
+  text.setAttribute("font-size", "34");
+  text.setAttribute("font-weight", "bold");
+  text.setAttribute("letter-spacing", "4");
+  text.textContent = patente.slice(0, 4) + " " + patente.slice(4);
+
+  svg.append(border, text);
+  return svg;
+}
+
+function showResult(output, patente, id) {
+  const line = document.createElement("p");
+  line.textContent = "Id " + id;
+  output.replaceChildren(plate(patente), line);
+}
+
+function showError(output, err) {
+  const message = document.createElement("p");
+  message.className = "error";
+  message.textContent = err.message;
+  output.replaceChildren(message);
+}
+
+document.getElementById("from-id").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const output = document.getElementById("from-id-output");
+  const id = event.target.elements.id.value.trim();
+  try {
+    const body = await api("../v1/patente/" + encodeURIComponent(id));
+    showResult(output, body.patente, id);
+  } catch (err) {
+    showError(output, err);
+  }
+});
+
+document.getElementById("from-patent").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const output = document.getElementById("from-patent-output");
+  const patente = event.target.elements.patente.value.trim().toUpperCase();
+  try {
+    const body = await api("../v1/id/" + encodeURIComponent(patente));
+    showResult(output, patente, body.id);
+  } catch (err) {
+    showError(output, err);
+  }
+});
+
+// batchRequest arma el request JSON-RPC de una linea: solo digitos es un id, lo demas una patente.
+function batchRequest(line, index) {
+  if (/^[0-9]+$/.test(line)) {
+    return { jsonrpc: "2.0", id: index, method: "patentFromID", params: { id: Number(line) } };
+  }
+  return { jsonrpc: "2.0", id: index, method: "idFromPatent", params: { patente: line.toUpperCase() } };
+}
+
+document.getElementById("batch").addEventListener("submit", async (event) => {
+  event.preventDefault();
+  const table = document.getElementById("batch-output");
+  const rows = table.querySelector("tbody");
+  const lines = event.target.elements.lines.value.split("\n").map((l) => l.trim()).filter((l) => l !== "");
+  rows.replaceChildren();
+  table.hidden = false;
+
+  for (let start = 0; start < lines.length; start += maxBatch) {
+    const chunk = lines.slice(start, start + maxBatch);
+    let responses;
+    try {
+      responses = await api("../rpc", {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify(chunk.map((line, i) => batchRequest(line, start + i))),
+      });
+    } catch (err) {
+      responses = chunk.map((_, i) => ({ id: start + i, error: { message: err.message } }));
+    }
+    const byID = new Map(responses.map((r) => [r.id, r]));
+    chunk.forEach((line, i) => {
+      const response = byID.get(start + i) || { error: { message: "sin respuesta" } };
+      const result = response.result || {};
+      const isID = /^[0-9]+$/.test(line);
+      const patente = isID ? result.patente : response.error ? "" : line.toUpperCase();
+      const row = document.createElement("tr");
+      for (const value of [line, isID ? line : result.id ?? "", "", response.error ? response.error.message : ""]) {
+        const cell = document.createElement("td");
+        cell.textContent = value;
+        row.append(cell);
+      }
+      if (patente) {
+        row.children[2].append(plate(patente));
+      }
+      if (response.error) {
+        row.className = "error";
+      }
+      rows.append(row);
+    });
+  }
+});
@@ -0,0 +1,22 @@
+// Funciones compartidas por las paginas de /ui: la credencial y los llamados a la api.
+"use strict";
+
+const credential = document.getElementById("credential");
+credential.value = sessionStorage.getItem("credential") || "";
+credential.addEventListener("change", () => sessionStorage.setItem("credential", credential.value));
+
+// api llama a la api y retorna el JSON, los errores lanzan el detail del problem JSON.
+async function api(path, options = {}) {
+  const headers = { Accept: "application/json", ...(options.headers || {}) };
+  if (credential.value) {
+    headers.Authorization = "Bearer " + credential.value;
+  }
+  const response = await fetch(path, { ...options, headers });
+  const type = response.headers.get("Content-Type") || "";
+  const body = type.includes("json") ? await response.json() : await response.text();
+  if (!response.ok) {
+    const message = body.detail || body.code || response.status + " " + response.statusText;
+    throw new Error(message);
+  }
+  return body;
+}
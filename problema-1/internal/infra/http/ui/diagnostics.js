@@ -0,0 +1,41 @@
+// Pagina de diagnostico: muestra /diagnostics, que exige el scope admin.
+"use strict";
+
+function fill(list, entries) {
+  list.replaceChildren();
+  for (const [name, value] of entries) {
+    const term = document.createElement("dt");
+    term.textContent = name;
+    const detail = document.createElement("dd");
+    detail.textContent = value;
+    list.append(term, detail);
+  }
+}
+
+async function load() {
+  const error = document.getElementById("error");
+  try {
+    const d = await api("../diagnostics");
+    error.hidden = true;
+    fill(document.getElementById("health"), Object.entries(d.health));
+    fill(document.getElementById("version"), [
+      ["version", d.version],
+      ["go", d.go_version],
+      ["iniciado", d.started_at],
+      ["uptime", d.uptime],
+    ]);
+    fill(document.getElementById("config"), Object.entries(d.config).sort(([a], [b]) => a.localeCompare(b)));
+    const routes = document.getElementById("routes");
+    routes.replaceChildren(...d.routes.map((route) => {
+      const item = document.createElement("li");
+      item.textContent = route;
+      return item;
+    }));
+  } catch (err) {
+    error.textContent = err.message;
+    error.hidden = false;
+  }
+}
+
+document.getElementById("refresh").addEventListener("click", load);
+load();
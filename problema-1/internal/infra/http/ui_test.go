@@ -0,0 +1,116 @@
+package http_adapter
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/docopt/docopt-go"
+)
+
+func TestUI(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		path        string
+		contentType string
+	}{
+		{"/ui/", "text/html"},
+		{"/ui/diagnostics.html", "text/html"},
+		{"/ui/app.js", "text/javascript"},
+		{"/ui/style.css", "text/css"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Expected Content-Type %s, got %s", tt.contentType, got)
+			}
+			if got := rec.Header().Get("Content-Security-Policy"); !strings.Contains(got, "default-src 'self'") {
+				t.Errorf("Expected CSP allowing the page assets, got %q", got)
+			}
+		})
+	}
+}
+
+// la interfaz tiene que funcionar sin acceso a internet, ningun archivo puede cargar recursos de
+// otro origen
+func TestUIOffline(t *testing.T) {
+	urlRX := regexp.MustCompile(`(https?:)?//[a-zA-Z0-9.-]+\.[a-z]{2,}`)
+	fs.WalkDir(uiFS, "ui", func(path string, d fs.DirEntry, err error) error {
+		if d.IsDir() {
+			return nil
+		}
+		content, _ := uiFS.ReadFile(path)
+		for _, match := range urlRX.FindAllString(string(content), -1) {
+			// el namespace de SVG es un identificador, no se descarga
+			if match != "http://www.w3.org" {
+				t.Errorf("%s references an external resource %s", path, match)
+			}
+		}
+		return nil
+	})
+}
+
+func TestDiagnostics(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.started = time.Now().Add(-time.Minute)
+	h.config = redactConfig(docopt.Opts{
+		"--host":       "0.0.0.0",
+		"--tls-key":    "/etc/patentes/key.pem",
+		"--jwt-jwks":   "/etc/patentes/jwks.json",
+		"--keys":       nil,
+		"--dev-tls":    false,
+		"--dry-run":    true,
+		"--help":       false,
+		"serve":        true,
+		"--rate":       "10",
+		"--quota-file": "/var/lib/quota.json",
+	})
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	rec := httptest.NewRecorder()
+	h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/diagnostics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var got diagnostics
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("Invalid body: %v", err)
+	}
+
+	expectedConfig := map[string]string{
+		"host":       "0.0.0.0",
+		"tls-key":    redacted,
+		"jwt-jwks":   redacted,
+		"dry-run":    "true",
+		"rate":       "10",
+		"quota-file": redacted,
+	}
+	if len(got.Config) != len(expectedConfig) {
+		t.Errorf("Expected config %v, got %v", expectedConfig, got.Config)
+	}
+	for name, value := range expectedConfig {
+		if got.Config[name] != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, got.Config[name])
+		}
+	}
+	if got.Version != version || got.Health["app"] != "ok" || got.Uptime != "1m0s" {
+		t.Errorf("Unexpected diagnostics %+v", got)
+	}
+}
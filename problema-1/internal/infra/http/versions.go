@@ -0,0 +1,43 @@
+package http_adapter
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// deprecatedSince es la fecha desde la que las rutas sin version estan deprecadas.
+var deprecatedSince = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+var versionRX = regexp.MustCompile(`^([A-Z]+ )/(v[0-9]+)(/.*)$`)
+
+// routeVersion retorna la version de un patron, v1 para "GET /v1/patente/{id}", vacio si no tiene.
+func routeVersion(pattern string) string {
+	if match := versionRX.FindStringSubmatch(pattern); match != nil {
+		return match[2]
+	}
+	return ""
+}
+
+// legacyPattern quita la version del patron, "GET /v1/patente/{id}" queda "GET /patente/{id}".
+func legacyPattern(pattern string) string {
+	if match := versionRX.FindStringSubmatch(pattern); match != nil {
+		return match[1] + match[3]
+	}
+	return pattern
+}
+
+// deprecated marca las respuestas de una ruta sin version con Deprecation (RFC 9745), Sunset
+// (RFC 8594) y un Link a la misma ruta con version.
+func (h *HTTP) deprecated(version string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("Deprecation", "@"+strconv.FormatInt(deprecatedSince.Unix(), 10))
+		if !h.sunset.IsZero() {
+			header.Set("Sunset", h.sunset.UTC().Format(http.TimeFormat))
+		}
+		header.Add("Link", "</"+version+r.URL.Path+`>; rel="successor-version"`)
+		next.ServeHTTP(w, r)
+	})
+}
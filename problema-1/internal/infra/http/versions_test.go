@@ -0,0 +1,96 @@
+package http_adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+)
+
+func TestVersionedRoutes(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.metrics = newHTTPMetrics(func() map[string]uint64 { return nil })
+	h.sunset = time.Date(2027, 6, 30, 0, 0, 0, 0, time.UTC)
+	h.SetRoutes()
+	h.SetMiddlewares()
+
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		deprecation bool
+		link        string
+	}{
+		{"v1 patente", "/v1/patente/1", `{"patente":"AAAA000"}`, false, ""},
+		{"v1 id", "/v1/id/AAAA000", `{"id":1}`, false, ""},
+		{"legacy patente", "/patente/1", `{"patente":"AAAA000"}`, true, `</v1/patente/1>; rel="successor-version"`},
+		{"legacy id", "/id/AAAA000", `{"id":1}`, true, `</v1/id/AAAA000>; rel="successor-version"`},
+		{"unversioned infrastructure", "/healthcheck", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if tt.body != "" && strings.TrimSpace(rec.Body.String()) != tt.body {
+				t.Errorf("Expected body %s, got %s", tt.body, rec.Body.String())
+			}
+			if got := rec.Header().Get("Deprecation") != ""; got != tt.deprecation {
+				t.Errorf("Expected Deprecation header %v, got %q", tt.deprecation, rec.Header().Get("Deprecation"))
+			}
+			if !tt.deprecation {
+				return
+			}
+			if got := rec.Header().Get("Deprecation"); got != "@1792368000" {
+				t.Errorf("Expected Deprecation @1792368000, got %q", got)
+			}
+			if got := rec.Header().Get("Sunset"); got != "Wed, 30 Jun 2027 00:00:00 GMT" {
+				t.Errorf("Expected Sunset date, got %q", got)
+			}
+			if got := rec.Header().Get("Link"); got != tt.link {
+				t.Errorf("Expected Link %s, got %s", tt.link, got)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	h.metrics.registry.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, expected := range []string{
+		`http_requests_by_version_total{version="v1"} 2`,
+		`http_requests_by_version_total{version="legacy"} 2`,
+	} {
+		if !strings.Contains(rec.Body.String(), expected) {
+			t.Errorf("Expected %s in metrics", expected)
+		}
+	}
+}
+
+func TestLegacyPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		version string
+		legacy  string
+	}{
+		{"GET /v1/patente/{id}", "v1", "GET /patente/{id}"},
+		{"POST /v2/rpc", "v2", "POST /rpc"},
+		{"GET /healthcheck", "", "GET /healthcheck"},
+		{"GET /version/x", "", "GET /version/x"},
+	}
+
+	for _, tt := range tests {
+		if got := routeVersion(tt.pattern); got != tt.version {
+			t.Errorf("Expected version %q for %s, got %q", tt.version, tt.pattern, got)
+		}
+		if got := legacyPattern(tt.pattern); got != tt.legacy {
+			t.Errorf("Expected legacy pattern %q for %s, got %q", tt.legacy, tt.pattern, got)
+		}
+	}
+}
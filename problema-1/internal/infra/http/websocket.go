@@ -0,0 +1,295 @@
+package http_adapter
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Implementacion del lado servidor de WebSocket (RFC 6455) sin extensiones ni subprotocolos, solo lo
+// que necesita /v1/ws: mensajes de texto, fragmentacion, ping/pong y el cierre ordenado.
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// Codigos de cierre de RFC 6455.
+const (
+	wsCloseNormal          = 1000
+	wsCloseGoingAway       = 1001
+	wsCloseProtocolError   = 1002
+	wsCloseUnsupportedData = 1003
+	wsCloseInvalidPayload  = 1007
+	wsClosePolicyViolation = 1008
+	wsCloseTooBig          = 1009
+)
+
+// wsCloseError es un error de lectura que se responde con un frame de cierre con ese codigo.
+type wsCloseError struct {
+	code   int
+	reason string
+}
+
+func (e *wsCloseError) Error() string {
+	return fmt.Sprintf("websocket: close %d %s", e.code, e.reason)
+}
+
+// headerHasToken busca token en un header de lista separada por comas, sin distinguir mayusculas.
+func headerHasToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wsAccept es el Sec-WebSocket-Accept que corresponde a la key del cliente.
+func wsAccept(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// sameOrigin indica si el Origin del navegador es el mismo host del request, los clientes que no
+// son navegadores no envian Origin.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// wsUpgrade valida el handshake, responde 101 y toma la conexion. Si el request no es un handshake
+// valido responde el error y retorna nil. Los headers ya puestos por los middlewares, como
+// X-Request-ID, se incluyen en la respuesta 101.
+func (h *HTTP) wsUpgrade(w http.ResponseWriter, r *http.Request) *wsConn {
+	if !headerHasToken(r.Header, "Connection", "upgrade") || !headerHasToken(r.Header, "Upgrade", "websocket") {
+		w.Header().Set("Upgrade", "websocket")
+		h.writeProblem(w, r, http.StatusUpgradeRequired, CodeUpgradeRequired, "this endpoint only accepts WebSocket connections")
+		return nil
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		h.writeProblem(w, r, http.StatusUpgradeRequired, CodeUpgradeRequired, "unsupported WebSocket version, use 13")
+		return nil
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		h.writeProblem(w, r, http.StatusBadRequest, CodeInvalidRequest, "invalid Sec-WebSocket-Key")
+		return nil
+	}
+	// el navegador envia las cookies y credenciales de cualquier pagina que abra el socket, solo se
+	// aceptan el mismo origen y los permitidos por CORS
+	if !sameOrigin(r) && (h.corsConfig == nil || !h.corsConfig.allowsOrigin(r.Header.Get("Origin"))) {
+		h.writeProblem(w, r, http.StatusForbidden, CodeForbidden, "origin not allowed")
+		return nil
+	}
+
+	conn, brw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Failed to hijack connection", "error", err.Error())
+		h.writeProblem(w, r, http.StatusInternalServerError, CodeInternal, "")
+		return nil
+	}
+	// el servidor pudo dejar deadlines de sus timeouts, desde aca los maneja wsConn
+	conn.SetDeadline(time.Time{})
+
+	header := w.Header().Clone()
+	header.Set("Upgrade", "websocket")
+	header.Set("Connection", "Upgrade")
+	header.Set("Sec-WebSocket-Accept", wsAccept(key))
+	header.Del("Content-Type")
+	header.Del("Vary")
+	brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	header.Write(brw)
+	brw.WriteString("\r\n")
+	if err := brw.Flush(); err != nil {
+		conn.Close()
+		return nil
+	}
+	return &wsConn{conn: conn, reader: brw.Reader, maxMessage: wsMaxMessage}
+}
+
+// wsConn es una conexion WebSocket, las lecturas las hace una sola goroutine y las escrituras se
+// pueden hacer desde varias.
+type wsConn struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	maxMessage int
+
+	writeMu   sync.Mutex
+	closeSent bool
+}
+
+// readMessage retorna el siguiente mensaje de texto. Responde los ping y llama onPong con los pong,
+// un frame de cierre del cliente retorna io.EOF.
+func (c *wsConn) readMessage(onPong func()) (string, error) {
+	var message []byte
+	fragmented := false
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return "", err
+		}
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				return "", err
+			}
+			continue
+		case wsOpPong:
+			if onPong != nil {
+				onPong()
+			}
+			continue
+		case wsOpClose:
+			code := wsCloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.close(code, "")
+			return "", io.EOF
+		case wsOpBinary:
+			return "", &wsCloseError{wsCloseUnsupportedData, "only text messages are supported"}
+		case wsOpText:
+			if fragmented {
+				return "", &wsCloseError{wsCloseProtocolError, "expected a continuation frame"}
+			}
+		case wsOpContinuation:
+			if !fragmented {
+				return "", &wsCloseError{wsCloseProtocolError, "unexpected continuation frame"}
+			}
+		default:
+			return "", &wsCloseError{wsCloseProtocolError, "unknown opcode"}
+		}
+
+		if len(message)+len(payload) > c.maxMessage {
+			return "", &wsCloseError{wsCloseTooBig, fmt.Sprintf("messages are limited to %d bytes", c.maxMessage)}
+		}
+		message = append(message, payload...)
+		if !fin {
+			fragmented = true
+			continue
+		}
+		if !utf8.Valid(message) {
+			return "", &wsCloseError{wsCloseInvalidPayload, "text messages must be valid UTF-8"}
+		}
+		return string(message), nil
+	}
+}
+
+// readFrame lee un frame y le quita la mascara, los frames del cliente siempre vienen enmascarados.
+func (c *wsConn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin := head[0]&0x80 != 0
+	if head[0]&0x70 != 0 {
+		return false, 0, nil, &wsCloseError{wsCloseProtocolError, "extensions are not supported"}
+	}
+	opcode := head[0] & 0x0F
+	if head[1]&0x80 == 0 {
+		return false, 0, nil, &wsCloseError{wsCloseProtocolError, "client frames must be masked"}
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if opcode >= wsOpClose && (length > 125 || !fin) {
+		return false, 0, nil, &wsCloseError{wsCloseProtocolError, "invalid control frame"}
+	}
+	// se corta antes de leer el payload para no reservar memoria por un largo inventado
+	if length > uint64(c.maxMessage) {
+		return false, 0, nil, &wsCloseError{wsCloseTooBig, fmt.Sprintf("messages are limited to %d bytes", c.maxMessage)}
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame escribe un frame sin fragmentar, los del servidor no llevan mascara. Un cliente que no
+// lee hace fallar la escritura por el deadline en vez de bloquear la conexion.
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	if opcode == wsOpClose {
+		c.closeSent = true
+	}
+
+	frame := make([]byte, 0, len(payload)+10)
+	frame = append(frame, 0x80|opcode)
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		frame = append(frame, 126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	default:
+		frame = append(frame, 127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(len(payload)))
+	}
+	frame = append(frame, payload...)
+
+	c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// close envia el frame de cierre si no se envio antes, la conexion la cierra quien la abrio.
+func (c *wsConn) close(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	payload = append(payload, reason...)
+	err := c.writeFrame(wsOpClose, payload)
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
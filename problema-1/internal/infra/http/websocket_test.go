@@ -0,0 +1,304 @@
+package http_adapter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
+)
+
+// wsTestClient es un cliente WebSocket minimo, enmascara sus frames como pide RFC 6455.
+type wsTestClient struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+const wsTestKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+func dialWS(t *testing.T, server *httptest.Server) (*wsTestClient, *http.Response) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", wsTestKey)
+	if err := req.Write(conn); err != nil {
+		t.Fatalf("Failed to write handshake: %v", err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		t.Fatalf("Failed to read handshake: %v", err)
+	}
+	return &wsTestClient{t: t, conn: conn, reader: reader}, resp
+}
+
+func (c *wsTestClient) write(opcode byte, payload []byte) {
+	c.t.Helper()
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{0x80 | opcode}
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, 0x80|byte(len(payload)))
+	default:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	if _, err := c.conn.Write(frame); err != nil {
+		c.t.Fatalf("Failed to write frame: %v", err)
+	}
+}
+
+func (c *wsTestClient) read() (byte, []byte) {
+	c.t.Helper()
+	var head [2]byte
+	if _, err := c.reader.Read(head[:1]); err != nil {
+		c.t.Fatalf("Failed to read frame: %v", err)
+	}
+	if _, err := c.reader.Read(head[1:]); err != nil {
+		c.t.Fatalf("Failed to read frame: %v", err)
+	}
+	length := int(head[1] & 0x7F)
+	if length == 126 {
+		var ext [2]byte
+		c.reader.Read(ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, length)
+	for n := 0; n < length; {
+		m, err := c.reader.Read(payload[n:])
+		if err != nil {
+			c.t.Fatalf("Failed to read payload: %v", err)
+		}
+		n += m
+	}
+	return head[0] & 0x0F, payload
+}
+
+// readClose lee hasta el frame de cierre y retorna su codigo.
+func (c *wsTestClient) readClose() int {
+	c.t.Helper()
+	for {
+		opcode, payload := c.read()
+		if opcode == wsOpClose {
+			return int(binary.BigEndian.Uint16(payload))
+		}
+	}
+}
+
+func newWSTestServer(t *testing.T, hub *wsHub) *httptest.Server {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.ws = hub
+	h.SetRoutes()
+	h.SetMiddlewares()
+	server := httptest.NewServer(h.Handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocketConvert(t *testing.T) {
+	server := newWSTestServer(t, newWSHub(10, 10, 0, time.Minute))
+	client, resp := dialWS(t, server)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status 101, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("Expected the RFC 6455 accept key, got %q", got)
+	}
+
+	tests := []struct {
+		name     string
+		message  string
+		expected wsResult
+	}{
+		{
+			"prefix is partial",
+			`{"seq": 1, "input": "aa"}`,
+			wsResult{Seq: 1, Input: "aa", Normalized: "AA", State: wsStatePartial, Kind: "patente"},
+		},
+		{
+			"complete plate",
+			`{"seq": 2, "input": " aaaa001 "}`,
+			wsResult{Seq: 2, Input: " aaaa001 ", Normalized: "AAAA001", State: wsStateComplete, Kind: "patente", ID: 2, Patente: "AAAA001"},
+		},
+		{
+			"id",
+			`{"seq": 3, "input": "3"}`,
+			wsResult{Seq: 3, Input: "3", Normalized: "3", State: wsStateComplete, Kind: "id", ID: 3, Patente: "AAAA002"},
+		},
+		{
+			"invalid plate",
+			`{"seq": 4, "input": "A1"}`,
+			wsResult{Seq: 4, Input: "A1", Normalized: "A1", State: wsStateInvalid, Kind: "patente", Code: "bad_format"},
+		},
+		{
+			"not json",
+			`AAAA000`,
+			wsResult{State: wsStateError, Code: CodeInvalidRequest},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.t = t
+			client.write(wsOpText, []byte(tt.message))
+			opcode, payload := client.read()
+			if opcode != wsOpText {
+				t.Fatalf("Expected a text frame, got opcode %d", opcode)
+			}
+			var got wsResult
+			if err := json.Unmarshal(payload, &got); err != nil {
+				t.Fatalf("Invalid result %s: %v", payload, err)
+			}
+			got.Message = ""
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %s", tt.expected, payload)
+			}
+		})
+	}
+
+	client.t = t
+	client.write(wsOpPing, []byte("hi"))
+	if opcode, payload := client.read(); opcode != wsOpPong || string(payload) != "hi" {
+		t.Errorf("Expected pong hi, got opcode %d %q", opcode, payload)
+	}
+
+	client.write(wsOpText, []byte(strings.Repeat("A", wsMaxMessage+1)))
+	if code := client.readClose(); code != wsCloseTooBig {
+		t.Errorf("Expected close %d, got %d", wsCloseTooBig, code)
+	}
+}
+
+func TestWebSocketLimits(t *testing.T) {
+	hub := newWSHub(1, 1, 0, 50*time.Millisecond)
+	server := newWSTestServer(t, hub)
+
+	client, resp := dialWS(t, server)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status 101, got %d", resp.StatusCode)
+	}
+	// el servidor envia ping periodicos
+	if opcode, _ := client.read(); opcode != wsOpPing {
+		t.Errorf("Expected a ping, got opcode %d", opcode)
+	}
+	client.write(wsOpPong, nil)
+
+	_, resp = dialWS(t, server)
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("Expected 503 with Retry-After over the connection limit, got %d", resp.StatusCode)
+	}
+
+	hub.Shutdown()
+	if code := client.readClose(); code != wsCloseGoingAway {
+		t.Errorf("Expected close %d on shutdown, got %d", wsCloseGoingAway, code)
+	}
+}
+
+func TestWebSocketRate(t *testing.T) {
+	server := newWSTestServer(t, newWSHub(10, 10, 1, time.Minute))
+	client, _ := dialWS(t, server)
+	client.write(wsOpText, []byte(`{"seq": 1, "input": "A"}`))
+	client.write(wsOpText, []byte(`{"seq": 2, "input": "AB"}`))
+	if code := client.readClose(); code != wsClosePolicyViolation {
+		t.Errorf("Expected close %d over the message rate, got %d", wsClosePolicyViolation, code)
+	}
+}
+
+// cada conversion se cobra al cliente como un request, el limite de mensajes de la conexion no
+// alcanza con varias conexiones de la misma key
+func TestWebSocketClientLimits(t *testing.T) {
+	h := newTestHTTP(&strings.Builder{})
+	h.app = &app.App{}
+	h.maxBody = 1 << 20
+	h.ws = newWSHub(10, 1, 0, time.Minute)
+	// los dos upgrades consumen un token cada uno y quedan dos conversiones
+	h.limiter = ratelimit.NewLimiter(0.001, 4)
+	h.SetRoutes()
+	h.SetMiddlewares()
+	server := httptest.NewServer(h.Handler())
+	t.Cleanup(server.Close)
+
+	client, resp := dialWS(t, server)
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status 101, got %d", resp.StatusCode)
+	}
+	_, resp = dialWS(t, server)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 over the connections of the client, got %d", resp.StatusCode)
+	}
+
+	expected := []struct {
+		input string
+		state string
+		code  string
+	}{
+		{"1", wsStateComplete, ""},
+		// los inputs parciales no convierten y no se cobran
+		{"AA", wsStatePartial, ""},
+		{"AAAA000", wsStateComplete, ""},
+		{"2", wsStateError, CodeRateLimited},
+	}
+	for i, tt := range expected {
+		client.write(wsOpText, []byte(fmt.Sprintf(`{"seq": %d, "input": %q}`, i, tt.input)))
+		_, payload := client.read()
+		var got wsResult
+		json.Unmarshal(payload, &got)
+		if got.State != tt.state || got.Code != tt.code {
+			t.Errorf("Expected %s %s for %s, got %s", tt.state, tt.code, tt.input, payload)
+		}
+	}
+}
+
+func TestWebSocketHandshakeErrors(t *testing.T) {
+	server := newWSTestServer(t, newWSHub(10, 10, 0, time.Minute))
+
+	tests := []struct {
+		name         string
+		header       map[string]string
+		expectedCode int
+	}{
+		{"plain request", nil, http.StatusUpgradeRequired},
+		{"old version", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": wsTestKey}, http.StatusUpgradeRequired},
+		{"bad key", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "nope"}, http.StatusBadRequest},
+		{"foreign origin", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": wsTestKey, "Origin": "https://evil.example"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/v1/ws", nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.expectedCode {
+				t.Errorf("Expected status %d, got %d", tt.expectedCode, resp.StatusCode)
+			}
+		})
+	}
+}
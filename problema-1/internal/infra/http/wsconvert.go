@@ -0,0 +1,359 @@
+package http_adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/app"
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/ratelimit"
+	"github.com/docopt/docopt-go"
+)
+
+const (
+	// wsMaxMessage es el tamaño maximo de un mensaje del cliente, un input es una patente o un id
+	wsMaxMessage = 1024
+	// wsWriteTimeout corta la conexion de un cliente que no lee sus respuestas
+	wsWriteTimeout = 10 * time.Second
+)
+
+// Estados de un resultado de /v1/ws.
+const (
+	wsStatePartial  = "partial"
+	wsStateComplete = "complete"
+	wsStateInvalid  = "invalid"
+	wsStateError    = "error"
+)
+
+// wsHub limita las conexiones WebSocket abiertas, en total y por cliente, y les avisa cuando el
+// servidor se apaga, las conexiones tomadas con Hijack no las cierra http.Server.Shutdown.
+type wsHub struct {
+	// rate es la cantidad de mensajes por segundo de cada conexion, 0 no limita
+	rate         float64
+	pingInterval time.Duration
+	slots        chan struct{}
+
+	// perClient es el maximo de conexiones de un mismo cliente, clients las cuenta por clientKey
+	perClient int
+	mu        sync.Mutex
+	clients   map[string]int
+
+	shutdown  chan struct{}
+	closeOnce sync.Once
+}
+
+func newWSHub(maxConns int, perClient int, rate float64, pingInterval time.Duration) *wsHub {
+	return &wsHub{
+		rate:         rate,
+		pingInterval: pingInterval,
+		slots:        make(chan struct{}, maxConns),
+		perClient:    perClient,
+		clients:      map[string]int{},
+		shutdown:     make(chan struct{}),
+	}
+}
+
+// wsConfig lee --ws-max-conns, --ws-client-conns, --ws-rate y --ws-ping-interval.
+func wsConfig(opts docopt.Opts) (*wsHub, error) {
+	maxConnsStr, _ := opts.String("--ws-max-conns")
+	maxConns, err := strconv.Atoi(maxConnsStr)
+	if err != nil || maxConns < 1 {
+		return nil, fmt.Errorf("websocket: invalid --ws-max-conns %q", maxConnsStr)
+	}
+	perClientStr, _ := opts.String("--ws-client-conns")
+	perClient, err := strconv.Atoi(perClientStr)
+	if err != nil || perClient < 1 {
+		return nil, fmt.Errorf("websocket: invalid --ws-client-conns %q", perClientStr)
+	}
+	rateStr, _ := opts.String("--ws-rate")
+	rate, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || rate < 0 {
+		return nil, fmt.Errorf("websocket: invalid --ws-rate %q", rateStr)
+	}
+	pingStr, _ := opts.String("--ws-ping-interval")
+	ping, err := time.ParseDuration(pingStr)
+	if err != nil || ping <= 0 {
+		return nil, fmt.Errorf("websocket: invalid --ws-ping-interval %q", pingStr)
+	}
+	return newWSHub(maxConns, perClient, rate, ping), nil
+}
+
+// acquire reserva una conexion del cliente key, false si ya tiene perClient abiertas.
+func (hub *wsHub) acquire(key string) bool {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.clients[key] >= hub.perClient {
+		return false
+	}
+	hub.clients[key]++
+	return true
+}
+
+func (hub *wsHub) release(key string) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	if hub.clients[key]--; hub.clients[key] <= 0 {
+		delete(hub.clients, key)
+	}
+}
+
+// Shutdown cierra las conexiones abiertas con el codigo 1001, se registra con RegisterOnShutdown.
+func (hub *wsHub) Shutdown() {
+	hub.closeOnce.Do(func() { close(hub.shutdown) })
+}
+
+// wsInput es un mensaje del cliente, seq identifica el input en la respuesta.
+type wsInput struct {
+	Seq   int64  `json:"seq"`
+	Input string `json:"input"`
+	// invalid indica que el mensaje no es JSON
+	invalid bool
+}
+
+// wsResult es la respuesta a un input.
+type wsResult struct {
+	Seq        int64  `json:"seq"`
+	Input      string `json:"input"`
+	Normalized string `json:"normalized"`
+	State      string `json:"state"`
+	// Kind es patente o id segun lo que parece el input
+	Kind    string `json:"kind,omitempty"`
+	ID      uint   `json:"id,omitempty"`
+	Patente string `json:"patente,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Superseded es la cantidad de inputs anteriores que no se respondieron porque llego este
+	Superseded int `json:"superseded,omitempty"`
+}
+
+// wsInputs guarda solo el ultimo input sin procesar. Mientras el operador escribe solo importa el
+// ultimo, asi un cliente que envia mas rapido de lo que lee sus respuestas no acumula trabajo ni
+// memoria en el servidor.
+type wsInputs struct {
+	mu         sync.Mutex
+	pending    *wsInput
+	superseded int
+	notify     chan struct{}
+}
+
+func (q *wsInputs) put(in wsInput) {
+	q.mu.Lock()
+	if q.pending != nil {
+		q.superseded++
+	}
+	q.pending = &in
+	q.mu.Unlock()
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *wsInputs) take() (wsInput, int, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending == nil {
+		return wsInput{}, 0, false
+	}
+	in, superseded := *q.pending, q.superseded
+	q.pending, q.superseded = nil, 0
+	return in, superseded, true
+}
+
+// convertWS es GET /v1/ws, el cliente envia {"seq": 1, "input": "abc"} mientras el operador escribe
+// y recibe el estado de validacion, el input normalizado y la conversion cuando esta completo. Cada
+// conversion se cobra en el rate limit y la cuota del cliente como un request http.
+func (h *HTTP) convertWS(w http.ResponseWriter, r *http.Request) {
+	select {
+	case h.ws.slots <- struct{}{}:
+		defer func() { <-h.ws.slots }()
+	default:
+		w.Header().Set("Retry-After", "1")
+		h.writeProblem(w, r, http.StatusServiceUnavailable, CodeTooManyConnections, "too many open WebSocket connections")
+		return
+	}
+	key := h.clientKey(r)
+	if !h.ws.acquire(key) {
+		w.Header().Set("Retry-After", "1")
+		h.writeProblem(w, r, http.StatusTooManyRequests, CodeTooManyConnections, "too many open WebSocket connections for this client")
+		return
+	}
+	defer h.ws.release(key)
+
+	conn := h.wsUpgrade(w, r)
+	if conn == nil {
+		return
+	}
+	defer conn.conn.Close()
+	h.serveConvertWS(r.Context(), key, conn)
+}
+
+func (h *HTTP) serveConvertWS(ctx context.Context, key string, conn *wsConn) {
+	inputs := &wsInputs{notify: make(chan struct{}, 1)}
+	var limiter *ratelimit.Limiter
+	if h.ws.rate > 0 {
+		limiter = ratelimit.NewLimiter(h.ws.rate, int(math.Ceil(h.ws.rate)))
+	}
+
+	// cualquier frame del cliente, incluidos los pong, extiende el plazo, sin respuesta a dos ping
+	// seguidos la conexion se da por perdida
+	idle := 2 * h.ws.pingInterval
+	extend := func() { conn.conn.SetReadDeadline(time.Now().Add(idle)) }
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			extend()
+			message, err := conn.readMessage(extend)
+			if err != nil {
+				readErr <- err
+				return
+			}
+			if limiter != nil && !limiter.Allow("conn", time.Now()).Allowed {
+				readErr <- &wsCloseError{wsClosePolicyViolation, "too many messages"}
+				return
+			}
+			var in wsInput
+			if err := json.Unmarshal([]byte(message), &in); err != nil {
+				in = wsInput{invalid: true}
+			}
+			inputs.put(in)
+		}
+	}()
+
+	ping := time.NewTicker(h.ws.pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-inputs.notify:
+			in, superseded, ok := inputs.take()
+			if !ok {
+				continue
+			}
+			result := h.wsConvert(ctx, key, in)
+			result.Superseded = superseded
+			body, _ := json.Marshal(result)
+			if err := conn.writeFrame(wsOpText, body); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.writeFrame(wsOpPing, nil); err != nil {
+				return
+			}
+		case err := <-readErr:
+			var closeErr *wsCloseError
+			if errors.As(err, &closeErr) {
+				conn.close(closeErr.code, closeErr.reason)
+				waitClose(readErr)
+			}
+			return
+		case <-h.ws.shutdown:
+			conn.close(wsCloseGoingAway, "server shutting down")
+			waitClose(readErr)
+			return
+		}
+	}
+}
+
+// waitClose espera un momento el frame de cierre del cliente antes de cerrar la conexion, como pide
+// el cierre ordenado de RFC 6455.
+func waitClose(readErr <-chan error) {
+	select {
+	case <-readErr:
+	case <-time.After(time.Second):
+	}
+}
+
+var (
+	digitsRX = regexp.MustCompile(`^[0-9]+$`)
+	// platePrefixRX acepta lo que todavia puede completarse como una patente LLLLNNN
+	platePrefixRX = regexp.MustCompile(`^[A-Z]{0,4}$|^[A-Z]{4}[0-9]{1,2}$`)
+)
+
+// wsConvert valida un input parcial: solo digitos es un id y se convierte, el comienzo de una
+// patente es partial y una patente completa se convierte. Solo las conversiones se cobran a key.
+func (h *HTTP) wsConvert(ctx context.Context, key string, in wsInput) wsResult {
+	result := wsResult{
+		Seq:        in.Seq,
+		Input:      in.Input,
+		Normalized: strings.ToUpper(strings.TrimSpace(in.Input)),
+	}
+	switch {
+	case in.invalid:
+		result.State = wsStateError
+		result.Code = CodeInvalidRequest
+		result.Message = `messages must be JSON like {"seq": 1, "input": "AB"}`
+	case digitsRX.MatchString(result.Normalized):
+		result.Kind = "id"
+		if !h.wsCharge(ctx, key, &result) {
+			break
+		}
+		id, err := strconv.ParseUint(result.Normalized, 10, 64)
+		if err != nil {
+			// no entra en un uint64, app lo rechaza como fuera de rango
+			id = math.MaxUint64
+		}
+		patente, err := h.app.PatentFromID(ctx, uint(id))
+		if err != nil {
+			h.wsError(ctx, &result, err)
+			break
+		}
+		result.State = wsStateComplete
+		result.ID = uint(id)
+		result.Patente = patente
+	case platePrefixRX.MatchString(result.Normalized):
+		result.Kind = "patente"
+		result.State = wsStatePartial
+	default:
+		result.Kind = "patente"
+		if !h.wsCharge(ctx, key, &result) {
+			break
+		}
+		id, err := h.app.IDFromPatent(ctx, result.Normalized)
+		if err != nil {
+			h.wsError(ctx, &result, err)
+			break
+		}
+		result.State = wsStateComplete
+		result.ID = id
+		result.Patente = result.Normalized
+	}
+	return result
+}
+
+// wsCharge cobra una conversion al cliente, si no le quedan deja el error en result.
+func (h *HTTP) wsCharge(ctx context.Context, key string, result *wsResult) bool {
+	code, _ := h.charge(key, time.Now())
+	switch code {
+	case "":
+		return true
+	case CodeRateLimited:
+		result.Message = message(ctx, code, "too many requests")
+	case CodeQuotaExceeded:
+		result.Message = message(ctx, code, "daily quota exceeded")
+	}
+	result.State = wsStateError
+	result.Code = code
+	return false
+}
+
+// wsError es el equivalente de writeAppError para los resultados de /v1/ws.
+func (h *HTTP) wsError(ctx context.Context, result *wsResult, err error) {
+	code := app.ErrorCode(err)
+	if code == "" {
+		h.logger.ErrorContext(ctx, "Unexpected app error", "error", err.Error())
+		result.State = wsStateError
+		result.Code = CodeInternal
+		return
+	}
+	result.State = wsStateInvalid
+	result.Code = code
+	result.Message = appMessage(ctx, err)
+}
@@ -0,0 +1,193 @@
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sign arma un token firmado con la llave privada, es lo que haria el emisor de tokens.
+func sign(t *testing.T, alg, kid string, private any, claims map[string]any) string {
+	t.Helper()
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch k := private.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatalf("Failed to sign: %v", err)
+		}
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func b64(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("01234567890123456789012345678901")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	// las llaves publicas se cargan desde un JWKS local
+	jwks, _ := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "oct", "kid": "hs", "k": base64.RawURLEncoding.EncodeToString(secret)},
+		{"kty": "RSA", "kid": "rs", "alg": "RS256", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "es", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y)},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatalf("Failed to write jwks: %v", err)
+	}
+	keys, err := LoadJWKS(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	verifier, err := NewVerifier(Options{Keys: keys, Issuer: "https://auth.local", Audience: "patentes"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	valid := map[string]any{
+		"sub": "front", "iss": "https://auth.local", "aud": []string{"patentes", "otra"},
+		"exp": now.Add(time.Hour).Unix(), "nbf": now.Add(-time.Minute).Unix(),
+	}
+	with := func(key string, value any) map[string]any {
+		claims := map[string]any{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	otherRSA, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	tests := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"HS256", sign(t, HS256, "hs", secret, valid), nil},
+		{"RS256", sign(t, RS256, "rs", rsaKey, valid), nil},
+		{"ES256", sign(t, ES256, "es", ecKey, valid), nil},
+		{"wrong signer", sign(t, RS256, "rs", otherRSA, valid), ErrInvalidSignature},
+		{"alg confusion", sign(t, HS256, "rs", secret, valid), ErrUnknownKey},
+		{"alg none", sign(t, "none", "", nil, valid), ErrUnsupportedAlg},
+		{"unknown kid", sign(t, RS256, "nope", rsaKey, valid), ErrUnknownKey},
+		{"expired", sign(t, HS256, "hs", secret, with("exp", now.Add(-time.Minute).Unix())), ErrExpired},
+		{"missing exp", sign(t, HS256, "hs", secret, with("exp", nil)), ErrExpired},
+		{"not yet valid", sign(t, HS256, "hs", secret, with("nbf", now.Add(time.Hour).Unix())), ErrNotYetValid},
+		{"wrong issuer", sign(t, HS256, "hs", secret, with("iss", "https://evil")), ErrInvalidIssuer},
+		{"wrong audience", sign(t, HS256, "hs", secret, with("aud", "otra")), ErrInvalidAudience},
+		{"single audience", sign(t, HS256, "hs", secret, with("aud", "patentes")), nil},
+		{"malformed", "abc.def", ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(tt.token, now)
+			if !errors.Is(err, tt.expected) {
+				t.Fatalf("Expected error %v, got %v", tt.expected, err)
+			}
+			if err == nil && claims.String("sub") != "front" {
+				t.Errorf("Expected sub front, got %v", claims["sub"])
+			}
+		})
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	content := `{"rules": [
+		{"routes": ["GET /patente/{id}", "GET /id/{patente}"], "claims": {"scope": "convert:read"}},
+		{"routes": ["*"], "claims": {"roles": ["admin", "ops"], "iss": "https://auth.local"}}
+	]}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+	policy, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reader := Claims{"scope": "profile convert:read"}
+	ops := Claims{"roles": []any{"ops"}, "iss": "https://auth.local"}
+	opsOtherIssuer := Claims{"roles": []any{"ops"}, "iss": "https://other"}
+
+	tests := []struct {
+		name     string
+		route    string
+		claims   Claims
+		expected bool
+	}{
+		{"scope allows conversion", "GET /patente/{id}", reader, true},
+		{"scope does not allow metrics", "GET /metrics", reader, false},
+		{"role allows every route", "GET /metrics", ops, true},
+		{"every claim must match", "GET /metrics", opsOtherIssuer, false},
+		{"no claims", "GET /id/{patente}", Claims{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Allows(tt.route, tt.claims); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	policy := &Policy{Rules: []Rule{
+		{Routes: []string{"GET /v1/patente/{id}", "*"}},
+		{Routes: []string{"GET /patente/{id}"}},
+	}}
+
+	if err := policy.Check([]string{"GET /v1/patente/{id}", "GET /patente/{id}"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	err := policy.Check([]string{"GET /v1/patente/{id}"})
+	if err == nil || !strings.Contains(err.Error(), `rule 1 has unknown route "GET /patente/{id}"`) {
+		t.Errorf("Expected unknown route error, got %v", err)
+	}
+}
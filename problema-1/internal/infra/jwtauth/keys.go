@@ -0,0 +1,179 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// Algoritmos soportados.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+// Key es una llave de verificacion. El algoritmo queda fijo en la llave y no se toma del token, asi
+// un token no puede pedir que una llave publica RSA se use como secreto HMAC.
+type Key struct {
+	ID     string
+	Alg    string
+	secret []byte
+	rsa    *rsa.PublicKey
+	ecdsa  *ecdsa.PublicKey
+}
+
+// NewHMACKey crea una llave HS256 con un secreto compartido.
+func NewHMACKey(id string, secret []byte) (Key, error) {
+	if len(secret) < 32 {
+		return Key{}, errors.New("jwtauth: HS256 secret must be at least 32 bytes")
+	}
+	return Key{ID: id, Alg: HS256, secret: secret}, nil
+}
+
+// NewPublicKey crea una llave RS256 o ES256 (P-256) a partir de una llave publica.
+func NewPublicKey(id string, public any) (Key, error) {
+	switch k := public.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < 2048 {
+			return Key{}, errors.New("jwtauth: RSA keys must be at least 2048 bits")
+		}
+		return Key{ID: id, Alg: RS256, rsa: k}, nil
+	case *ecdsa.PublicKey:
+		if k.Curve != elliptic.P256() {
+			return Key{}, errors.New("jwtauth: ES256 requires a P-256 key")
+		}
+		return Key{ID: id, Alg: ES256, ecdsa: k}, nil
+	}
+	return Key{}, fmt.Errorf("jwtauth: unsupported public key type %T", public)
+}
+
+// LoadPEM lee una llave publica RSA o EC en formato PEM (PKIX o PKCS1).
+func LoadPEM(path string) (Key, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, fmt.Errorf("jwtauth: reading %q: %w", path, err)
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return Key{}, fmt.Errorf("jwtauth: %q has no PEM block", path)
+	}
+	var public any
+	switch block.Type {
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		public, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("jwtauth: unsupported PEM block %q in %q", block.Type, path)
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("jwtauth: parsing %q: %w", path, err)
+	}
+	return NewPublicKey("", public)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// oct
+	K string `json:"k"`
+}
+
+// LoadJWKS lee un archivo JWKS local con llaves RSA, EC P-256 u oct (HMAC).
+func LoadJWKS(path string) ([]Key, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtauth: reading %q: %w", path, err)
+	}
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return nil, fmt.Errorf("jwtauth: decoding %q: %w", path, err)
+	}
+
+	keys := make([]Key, 0, len(set.Keys))
+	for i, raw := range set.Keys {
+		if raw.Use != "" && raw.Use != "sig" {
+			continue
+		}
+		key, err := raw.key()
+		if err != nil {
+			return nil, fmt.Errorf("jwtauth: key %d (%q) in %q: %w", i, raw.Kid, path, err)
+		}
+		if raw.Alg != "" && raw.Alg != key.Alg {
+			return nil, fmt.Errorf("jwtauth: key %d (%q) in %q: alg %s does not match key type", i, raw.Kid, path, raw.Alg)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (j jwk) key() (Key, error) {
+	switch j.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(j.K)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid k: %w", err)
+		}
+		return NewHMACKey(j.Kid, secret)
+	case "RSA":
+		n, err := decodeInt(j.N)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid n: %w", err)
+		}
+		e, err := decodeInt(j.E)
+		if err != nil || !e.IsInt64() {
+			return Key{}, errors.New("invalid e")
+		}
+		return NewPublicKey(j.Kid, &rsa.PublicKey{N: n, E: int(e.Int64())})
+	case "EC":
+		if j.Crv != "P-256" {
+			return Key{}, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeInt(j.X)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid x: %w", err)
+		}
+		y, err := decodeInt(j.Y)
+		if err != nil {
+			return Key{}, fmt.Errorf("invalid y: %w", err)
+		}
+		public := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		// ECDH valida que el punto este en la curva
+		if _, err := public.ECDH(); err != nil {
+			return Key{}, fmt.Errorf("invalid point: %w", err)
+		}
+		return NewPublicKey(j.Kid, public)
+	}
+	return Key{}, fmt.Errorf("unsupported kty %q", j.Kty)
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,109 @@
+package jwtauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// Policy decide que rutas puede usar un token segun sus claims. Se lee de un archivo JSON como:
+//
+//	{
+//	  "rules": [
+//	    {"routes": ["GET /v1/patente/{id}", "GET /v1/id/{patente}"], "claims": {"scope": "convert:read"}},
+//	    {"routes": ["*"], "claims": {"roles": ["admin"]}}
+//	  ]
+//	}
+//
+// Las rutas son los patrones registrados en el mux y "*" aplica a todas. Un request se permite si
+// alguna regla incluye su ruta y todos los claims de la regla calzan. Un claim calza si alguno de los
+// valores de la regla esta en el claim del token, que puede ser un string, un arreglo o, en el caso
+// de scope, una lista separada por espacios.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+type Rule struct {
+	Routes []string          `json:"routes"`
+	Claims map[string]values `json:"claims"`
+}
+
+// values acepta un string o un arreglo de strings en el JSON.
+type values []string
+
+func (v *values) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*v = values{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return errors.New("claim values must be a string or an array of strings")
+	}
+	*v = many
+	return nil
+}
+
+// LoadPolicy lee y valida un archivo de politicas.
+func LoadPolicy(path string) (*Policy, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("jwtauth: reading %q: %w", path, err)
+	}
+	var policy Policy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("jwtauth: decoding %q: %w", path, err)
+	}
+	for i, rule := range policy.Rules {
+		if len(rule.Routes) == 0 {
+			return nil, fmt.Errorf("jwtauth: rule %d in %q has no routes", i, path)
+		}
+		for claim, allowed := range rule.Claims {
+			if len(allowed) == 0 {
+				return nil, fmt.Errorf("jwtauth: rule %d in %q has no values for claim %q", i, path, claim)
+			}
+		}
+	}
+	return &policy, nil
+}
+
+// Check retorna un error si alguna regla nombra una ruta que no esta en known, una ruta mal escrita
+// o de otra version nunca calzaria y sus tokens se rechazarian sin aviso.
+func (p *Policy) Check(known []string) error {
+	for i, rule := range p.Rules {
+		for _, route := range rule.Routes {
+			if route != "*" && !slices.Contains(known, route) {
+				return fmt.Errorf("jwtauth: rule %d has unknown route %q, routes are patterns like \"GET /v1/patente/{id}\"", i, route)
+			}
+		}
+	}
+	return nil
+}
+
+// Allows reporta si los claims permiten la ruta.
+func (p *Policy) Allows(route string, claims Claims) bool {
+	for _, rule := range p.Rules {
+		if !slices.Contains(rule.Routes, route) && !slices.Contains(rule.Routes, "*") {
+			continue
+		}
+		if rule.matches(claims) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matches(claims Claims) bool {
+	for claim, allowed := range r.Claims {
+		got := claims.Strings(claim)
+		if !slices.ContainsFunc(allowed, func(value string) bool {
+			return slices.Contains(got, value)
+		}) {
+			return false
+		}
+	}
+	return true
+}
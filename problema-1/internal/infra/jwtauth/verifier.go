@@ -0,0 +1,220 @@
+// Package jwtauth verifica JWT firmados con HS256, RS256 o ES256 contra llaves locales y evalua
+// politicas que relacionan claims con las rutas permitidas.
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrMalformed        = errors.New("jwtauth: malformed token")
+	ErrUnsupportedAlg   = errors.New("jwtauth: unsupported algorithm")
+	ErrUnknownKey       = errors.New("jwtauth: unknown key")
+	ErrInvalidSignature = errors.New("jwtauth: invalid signature")
+	ErrExpired          = errors.New("jwtauth: token expired")
+	ErrNotYetValid      = errors.New("jwtauth: token not valid yet")
+	ErrInvalidIssuer    = errors.New("jwtauth: invalid issuer")
+	ErrInvalidAudience  = errors.New("jwtauth: invalid audience")
+)
+
+// Claims son los claims del payload tal como vienen en el JSON.
+type Claims map[string]any
+
+// String retorna el claim si es un string.
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings retorna el claim como lista, aceptando un string, un arreglo de strings o, para scope, un
+// string separado por espacios como define RFC 8693.
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		if name == "scope" {
+			return strings.Fields(value)
+		}
+		return []string{value}
+	case []any:
+		out := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+type Options struct {
+	Keys []Key
+	// Issuer y Audience vacios no se validan.
+	Issuer   string
+	Audience string
+	// Leeway es la tolerancia de reloj para exp y nbf.
+	Leeway time.Duration
+}
+
+type Verifier struct {
+	keys     []Key
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+func NewVerifier(opts Options) (*Verifier, error) {
+	if len(opts.Keys) == 0 {
+		return nil, errors.New("jwtauth: at least one key is required")
+	}
+	return &Verifier{
+		keys:     opts.Keys,
+		issuer:   opts.Issuer,
+		audience: opts.Audience,
+		leeway:   opts.Leeway,
+	}, nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Verify valida la firma y los claims registrados (exp, nbf, iss, aud) del token. exp es
+// obligatorio para que no existan tokens validos para siempre.
+func (v *Verifier) Verify(token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, err
+	}
+	if h.Alg != HS256 && h.Alg != RS256 && h.Alg != ES256 {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlg, h.Alg)
+	}
+	key, err := v.key(h)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if err := verifySignature(key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.validate(claims, now); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// key busca la llave por kid y exige que su algoritmo sea el del header. Sin kid solo se acepta si
+// hay una unica llave para ese algoritmo.
+func (v *Verifier) key(h header) (Key, error) {
+	var candidates []Key
+	for _, key := range v.keys {
+		if key.Alg != h.Alg {
+			continue
+		}
+		if h.Kid != "" && key.ID == h.Kid {
+			return key, nil
+		}
+		candidates = append(candidates, key)
+	}
+	if h.Kid == "" && len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return Key{}, fmt.Errorf("%w: kid %q alg %s", ErrUnknownKey, h.Kid, h.Alg)
+}
+
+func verifySignature(key Key, signed string, signature []byte) error {
+	digest := sha256.Sum256([]byte(signed))
+	switch key.Alg {
+	case HS256:
+		mac := hmac.New(sha256.New, key.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return ErrInvalidSignature
+		}
+	case RS256:
+		if err := rsa.VerifyPKCS1v15(key.rsa, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+	case ES256:
+		// JWS usa la firma r || s de 32 bytes cada uno y no ASN.1
+		if len(signature) != 64 {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key.ecdsa, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlg, key.Alg)
+	}
+	return nil
+}
+
+func (v *Verifier) validate(claims Claims, now time.Time) error {
+	exp, ok := numericDate(claims, "exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrExpired)
+	}
+	if !now.Before(exp.Add(v.leeway)) {
+		return ErrExpired
+	}
+	if nbf, ok := numericDate(claims, "nbf"); ok && now.Add(v.leeway).Before(nbf) {
+		return ErrNotYetValid
+	}
+	if v.issuer != "" && claims.String("iss") != v.issuer {
+		return ErrInvalidIssuer
+	}
+	if v.audience != "" && !slices.Contains(claims.Strings("aud"), v.audience) {
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+func numericDate(claims Claims, name string) (time.Time, bool) {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds := int64(value)
+	return time.Unix(seconds, int64((value-float64(seconds))*1e9)), true
+}
+
+func decodeSegment(segment string, out any) error {
+	content, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(content, out); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+// Package metrics implementa un registro minimo de metricas que se exporta en el formato de texto
+// de Prometheus, para no depender del cliente oficial.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets son los limites en segundos que usa Prometheus por defecto para latencias.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type collector interface {
+	write(w *bufio.Writer)
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	names      map[string]bool
+	collectors []collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]bool{}}
+}
+
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names[name] = true
+	r.collectors = append(r.collectors, c)
+}
+
+// WriteTo escribe todas las metricas en el formato de texto de Prometheus.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	collectors := append([]collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+	for _, c := range collectors {
+		c.write(buf)
+	}
+	err := buf.Flush()
+	return counter.n, err
+}
+
+// Handler sirve las metricas para el scrape de Prometheus.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	return n, err
+}
+
+// vec guarda una serie por cada combinacion de valores de labels.
+type vec[T any] struct {
+	name   string
+	help   string
+	labels []string
+	mu     sync.Mutex
+	series map[string]*T
+	values map[string][]string
+	newT   func() *T
+}
+
+func (v *vec[T]) get(labelValues []string) *T {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", v.name, len(v.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	s, ok := v.series[key]
+	if !ok {
+		s = v.newT()
+		v.series[key] = s
+		v.values[key] = append([]string(nil), labelValues...)
+	}
+	return s
+}
+
+// sorted retorna las series ordenadas por sus labels para que la salida sea estable.
+func (v *vec[T]) sorted() ([]*T, [][]string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	series := make([]*T, len(keys))
+	values := make([][]string, len(keys))
+	for i, key := range keys {
+		series[i] = v.series[key]
+		values[i] = v.values[key]
+	}
+	return series, values
+}
+
+type counterValue struct {
+	mu    sync.Mutex
+	value float64
+}
+
+type CounterVec struct {
+	vec[counterValue]
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{vec[counterValue]{
+		name: name, help: help, labels: labels,
+		series: map[string]*counterValue{},
+		values: map[string][]string{},
+		newT:   func() *counterValue { return &counterValue{} },
+	}}
+	r.register(name, c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	s := c.get(labelValues)
+	s.mu.Lock()
+	s.value += delta
+	s.mu.Unlock()
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	series, values := c.sorted()
+	for i, s := range series {
+		s.mu.Lock()
+		value := s.value
+		s.mu.Unlock()
+		writeSample(w, c.name, c.labels, values[i], "", "", value)
+	}
+}
+
+type histogramValue struct {
+	mu     sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type HistogramVec struct {
+	vec[histogramValue]
+	buckets []float64
+}
+
+// NewHistogramVec crea un histograma, si buckets es nil se usan DefaultBuckets.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	h := &HistogramVec{buckets: buckets}
+	h.vec = vec[histogramValue]{
+		name: name, help: help, labels: labels,
+		series: map[string]*histogramValue{},
+		values: map[string][]string{},
+		newT: func() *histogramValue {
+			return &histogramValue{counts: make([]uint64, len(buckets))}
+		},
+	}
+	r.register(name, h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	s := h.get(labelValues)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	series, values := h.sorted()
+	for i, s := range series {
+		s.mu.Lock()
+		counts := append([]uint64(nil), s.counts...)
+		sum, count := s.sum, s.count
+		s.mu.Unlock()
+		for j, bound := range h.buckets {
+			writeSample(w, h.name+"_bucket", h.labels, values[i], "le", formatFloat(bound), float64(counts[j]))
+		}
+		writeSample(w, h.name+"_bucket", h.labels, values[i], "le", "+Inf", float64(count))
+		writeSample(w, h.name+"_sum", h.labels, values[i], "", "", sum)
+		writeSample(w, h.name+"_count", h.labels, values[i], "", "", float64(count))
+	}
+}
+
+// funcCollector lee los valores al momento del scrape, sirve para metricas que ya se llevan en otro
+// lado como las estadisticas del runtime.
+type funcCollector struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+	fn     func() map[string]float64
+}
+
+// NewGaugeFunc registra un gauge sin labels cuyo valor se obtiene de fn en cada scrape.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.register(name, &funcCollector{name: name, help: help, kind: "gauge", fn: func() map[string]float64 {
+		return map[string]float64{"": fn()}
+	}})
+}
+
+// NewCounterFunc registra un counter con un unico label, fn retorna el valor por cada valor del label.
+// Con label vacio el counter no tiene labels y fn debe retornar el valor en la clave "".
+func (r *Registry) NewCounterFunc(name, help, label string, fn func() map[string]float64) {
+	var labels []string
+	if label != "" {
+		labels = []string{label}
+	}
+	r.register(name, &funcCollector{name: name, help: help, kind: "counter", labels: labels, fn: fn})
+}
+
+// NewInfo registra una metrica constante en 1 cuyos labels llevan la informacion, como build_info.
+func (r *Registry) NewInfo(name, help string, labels map[string]string) {
+	names := make([]string, 0, len(labels))
+	for label := range labels {
+		names = append(names, label)
+	}
+	sort.Strings(names)
+	values := make([]string, len(names))
+	for i, label := range names {
+		values[i] = labels[label]
+	}
+	r.register(name, &infoCollector{name: name, help: help, labels: names, values: values})
+}
+
+type infoCollector struct {
+	name   string
+	help   string
+	labels []string
+	values []string
+}
+
+func (c *infoCollector) write(w *bufio.Writer) {
+	writeHeader(w, c.name, c.help, "gauge")
+	writeSample(w, c.name, c.labels, c.values, "", "", 1)
+}
+
+func (c *funcCollector) write(w *bufio.Writer) {
+	writeHeader(w, c.name, c.help, c.kind)
+	values := c.fn()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var labelValues []string
+		if len(c.labels) > 0 {
+			labelValues = []string{key}
+		}
+		writeSample(w, c.name, c.labels, labelValues, "", "", values[key])
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", label, escapeLabel(values[i]))
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", extraLabel, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(value))
+	w.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(value string) string { return labelEscaper.Replace(value) }
+func escapeHelp(value string) string  { return helpEscaper.Replace(value) }
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExposition(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("requests_total", "Requests.", "route", "status")
+	latency := r.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	r.NewGaugeFunc("answer", "The answer.", func() float64 { return 42 })
+	r.NewCounterFunc("errors_total", "Errors by reason.", "reason", func() map[string]float64 {
+		return map[string]float64{"empty": 2, "bad_format": 1}
+	})
+	r.NewInfo("build_info", "Build info.", map[string]string{"version": "0.0.1"})
+
+	requests.Inc("GET /id/{patente}", "200")
+	requests.Add(2, "GET /id/{patente}", "200")
+	requests.Inc(`with "quotes"`, "500")
+	latency.Observe(0.05, "a")
+	latency.Observe(0.5, "a")
+	latency.Observe(3, "a")
+
+	out := &strings.Builder{}
+	if _, err := r.WriteTo(out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"# HELP requests_total Requests.",
+		"# TYPE requests_total counter",
+		`requests_total{route="GET /id/{patente}",status="200"} 3`,
+		`requests_total{route="with \"quotes\"",status="500"} 1`,
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{route="a",le="0.1"} 1`,
+		`latency_seconds_bucket{route="a",le="1"} 2`,
+		`latency_seconds_bucket{route="a",le="+Inf"} 3`,
+		`latency_seconds_sum{route="a"} 3.55`,
+		`latency_seconds_count{route="a"} 3`,
+		"# TYPE answer gauge",
+		"answer 42",
+		`errors_total{reason="bad_format"} 1`,
+		`errors_total{reason="empty"} 2`,
+		`build_info{version="0.0.1"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRegisterTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic registering the same name twice")
+		}
+	}()
+	r := NewRegistry()
+	r.NewCounterVec("dup", "Dup.")
+	r.NewCounterVec("dup", "Dup.")
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RegisterRuntime agrega metricas del runtime de Go. Los gauges y process_start_time_seconds usan
+// los mismos nombres que el cliente oficial de Prometheus, asi los dashboards existentes funcionan
+// sin cambios. go_gc_cycles_total y go_gc_pause_seconds_total son propios, el cliente oficial
+// expone el GC como el summary go_gc_duration_seconds.
+func RegisterRuntime(r *Registry) {
+	start := float64(time.Now().Unix())
+
+	// ReadMemStats detiene el mundo, se cachea un segundo para que varios gauges del mismo scrape
+	// no lo llamen cada uno
+	var (
+		mu    sync.Mutex
+		stats runtime.MemStats
+		read  time.Time
+	)
+	memStats := func() runtime.MemStats {
+		mu.Lock()
+		defer mu.Unlock()
+		if time.Since(read) > time.Second {
+			runtime.ReadMemStats(&stats)
+			read = time.Now()
+		}
+		return stats
+	}
+
+	r.NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+	r.NewGaugeFunc("go_threads", "Number of OS threads created.", func() float64 {
+		n, _ := runtime.ThreadCreateProfile(nil)
+		return float64(n)
+	})
+	r.NewGaugeFunc("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", func() float64 {
+		return float64(memStats().Alloc)
+	})
+	r.NewGaugeFunc("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", func() float64 {
+		return float64(memStats().HeapInuse)
+	})
+	r.NewGaugeFunc("go_memstats_sys_bytes", "Number of bytes obtained from system.", func() float64 {
+		return float64(memStats().Sys)
+	})
+	r.NewCounterFunc("go_gc_cycles_total", "Number of completed GC cycles.", "", func() map[string]float64 {
+		return map[string]float64{"": float64(memStats().NumGC)}
+	})
+	r.NewCounterFunc("go_gc_pause_seconds_total", "Total GC pause time in seconds.", "", func() map[string]float64 {
+		return map[string]float64{"": float64(memStats().PauseTotalNs) / 1e9}
+	})
+	r.NewGaugeFunc("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", func() float64 {
+		return start
+	})
+}
+
+// RegisterBuildInfo agrega build_info con la version del servicio, la version de Go y el commit
+// si el binario se compilo con informacion de vcs.
+func RegisterBuildInfo(r *Registry, version string) {
+	labels := map[string]string{
+		"version":   version,
+		"goversion": runtime.Version(),
+		"revision":  "unknown",
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				labels["revision"] = setting.Value
+			}
+		}
+	}
+	r.NewInfo("build_info", "Build information of the service, the value is always 1.", labels)
+}
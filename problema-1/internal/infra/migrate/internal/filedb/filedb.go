@@ -0,0 +1,406 @@
+// Package filedb registra un driver de database/sql llamado "filedb" que guarda su estado en un
+// archivo JSON. No es una base de datos real, solo sirve para los tests del runner de migraciones:
+// entiende CREATE TABLE, INSERT, DELETE ... WHERE col = ? y SELECT simples, y cualquier otra
+// sentencia la agrega a un log dentro del mismo archivo sin ejecutarla. Por eso es interno y el
+// binario no lo registra.
+package filedb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const DriverName = "filedb"
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+var (
+	createRX = regexp.MustCompile(`(?is)^CREATE\s+TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?(\w+)\s*\((.*)\)$`)
+	insertRX = regexp.MustCompile(`(?is)^INSERT\s+INTO\s+(\w+)\s*\(([^)]*)\)\s*VALUES\s*\(([^)]*)\)$`)
+	deleteRX = regexp.MustCompile(`(?is)^DELETE\s+FROM\s+(\w+)\s+WHERE\s+(\w+)\s*=\s*(?:\?|\$[0-9]+)$`)
+	selectRX = regexp.MustCompile(`(?is)^SELECT\s+(.+?)\s+FROM\s+(\w+)(?:\s+ORDER\s+BY\s+(\w+))?$`)
+)
+
+// ErrDuplicateKey se retorna al insertar una fila con una clave primaria que ya existe.
+var ErrDuplicateKey = errors.New("filedb: duplicate primary key")
+
+// el mutex es global porque varias conexiones (o varios sql.DB) pueden apuntar al mismo archivo
+var mu sync.Mutex
+
+type table struct {
+	Columns []string `json:"columns"`
+	// Key es el indice de la columna PRIMARY KEY, -1 si no tiene.
+	Key  int     `json:"key"`
+	Rows [][]any `json:"rows"`
+}
+
+type state struct {
+	Tables map[string]*table `json:"tables"`
+	Log    []string          `json:"log"`
+}
+
+// State lee el archivo de la base de datos, pensado para inspeccionarlo en tests.
+// Retorna las filas de cada tabla y el log de sentencias ejecutadas.
+func State(path string) (map[string][][]any, []string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	s, err := load(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	tables := map[string][][]any{}
+	for name, t := range s.Tables {
+		tables[name] = t.Rows
+	}
+	return tables, s.Log, nil
+}
+
+func load(path string) (*state, error) {
+	s := &state{Tables: map[string]*table{}}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || len(content) == 0 {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("filedb: reading %q: %w", path, err)
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(content)))
+	// UseNumber para no perder enteros grandes al pasar por float64
+	decoder.UseNumber()
+	if err := decoder.Decode(s); err != nil {
+		return nil, fmt.Errorf("filedb: decoding %q: %w", path, err)
+	}
+	if s.Tables == nil {
+		s.Tables = map[string]*table{}
+	}
+	return s, nil
+}
+
+func (s *state) save(path string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("filedb: encoding state: %w", err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("filedb: writing %q: %w", path, err)
+	}
+	return nil
+}
+
+type Driver struct{}
+
+// Open recibe como nombre la ruta del archivo JSON.
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	if name == "" {
+		return nil, errors.New("filedb: empty file path")
+	}
+	return &conn{path: name}, nil
+}
+
+type conn struct {
+	path string
+	tx   *state
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error { return nil }
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.tx != nil {
+		return nil, errors.New("filedb: transaction already open")
+	}
+	mu.Lock()
+	s, err := load(c.path)
+	mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	c.tx = s
+	return c, nil
+}
+
+// Commit guarda el estado de la transaccion, no detecta conflictos con otras conexiones.
+func (c *conn) Commit() error {
+	s := c.tx
+	c.tx = nil
+	if s == nil {
+		return errors.New("filedb: no transaction open")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	return s.save(c.path)
+}
+
+func (c *conn) Rollback() error {
+	c.tx = nil
+	return nil
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.tx != nil {
+		n, err := c.tx.exec(query, values(args))
+		return driver.RowsAffected(n), err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	s, err := load(c.path)
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.exec(query, values(args))
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(n), s.save(c.path)
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	s := c.tx
+	if s == nil {
+		mu.Lock()
+		loaded, err := load(c.path)
+		mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		s = loaded
+	}
+	return s.query(query)
+}
+
+func values(args []driver.NamedValue) []any {
+	vals := make([]any, len(args))
+	for i, arg := range args {
+		vals[i] = arg.Value
+	}
+	return vals
+}
+
+func (s *state) exec(query string, args []any) (int64, error) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	// varias sentencias juntas no se interpretan, solo se registran
+	if strings.Contains(query, ";") {
+		s.Log = append(s.Log, query)
+		return 0, nil
+	}
+
+	if match := createRX.FindStringSubmatch(query); match != nil {
+		name := strings.ToLower(match[1])
+		if _, ok := s.Tables[name]; ok {
+			return 0, nil
+		}
+		t := &table{Key: -1, Rows: [][]any{}}
+		for i, def := range strings.Split(match[2], ",") {
+			fields := strings.Fields(def)
+			if len(fields) == 0 {
+				continue
+			}
+			t.Columns = append(t.Columns, strings.ToLower(fields[0]))
+			if strings.Contains(strings.ToUpper(def), "PRIMARY KEY") {
+				t.Key = i
+			}
+		}
+		s.Tables[name] = t
+		return 0, nil
+	}
+
+	if match := insertRX.FindStringSubmatch(query); match != nil {
+		t, ok := s.Tables[strings.ToLower(match[1])]
+		if !ok {
+			return 0, fmt.Errorf("filedb: no such table %q", match[1])
+		}
+		columns := splitList(match[2])
+		if len(columns) != len(args) {
+			return 0, fmt.Errorf("filedb: insert has %d columns and %d args", len(columns), len(args))
+		}
+		row := make([]any, len(t.Columns))
+		for i, column := range columns {
+			idx := indexOf(t.Columns, column)
+			if idx < 0 {
+				return 0, fmt.Errorf("filedb: no such column %q", column)
+			}
+			row[idx] = args[i]
+		}
+		if t.Key >= 0 {
+			for _, existing := range t.Rows {
+				if equal(existing[t.Key], row[t.Key]) {
+					return 0, fmt.Errorf("%w: %v", ErrDuplicateKey, row[t.Key])
+				}
+			}
+		}
+		t.Rows = append(t.Rows, row)
+		return 1, nil
+	}
+
+	if match := deleteRX.FindStringSubmatch(query); match != nil {
+		t, ok := s.Tables[strings.ToLower(match[1])]
+		if !ok {
+			return 0, fmt.Errorf("filedb: no such table %q", match[1])
+		}
+		idx := indexOf(t.Columns, strings.ToLower(match[2]))
+		if idx < 0 || len(args) != 1 {
+			return 0, fmt.Errorf("filedb: invalid delete %q", query)
+		}
+		kept := t.Rows[:0]
+		var n int64
+		for _, row := range t.Rows {
+			if equal(row[idx], args[0]) {
+				n++
+				continue
+			}
+			kept = append(kept, row)
+		}
+		t.Rows = kept
+		return n, nil
+	}
+
+	// el resto de sentencias (el contenido de las migraciones) solo se registra
+	s.Log = append(s.Log, query)
+	return 0, nil
+}
+
+func (s *state) query(query string) (driver.Rows, error) {
+	query = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(query), ";"))
+	match := selectRX.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("filedb: unsupported query %q", query)
+	}
+	t, ok := s.Tables[strings.ToLower(match[2])]
+	if !ok {
+		return nil, fmt.Errorf("filedb: no such table %q", match[2])
+	}
+
+	columns := splitList(match[1])
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		indexes[i] = indexOf(t.Columns, column)
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("filedb: no such column %q", column)
+		}
+	}
+
+	rows := make([][]any, len(t.Rows))
+	copy(rows, t.Rows)
+	if match[3] != "" {
+		order := indexOf(t.Columns, strings.ToLower(match[3]))
+		if order < 0 {
+			return nil, fmt.Errorf("filedb: no such column %q", match[3])
+		}
+		sort.SliceStable(rows, func(i, j int) bool {
+			return less(rows[i][order], rows[j][order])
+		})
+	}
+
+	result := &resultRows{columns: columns}
+	for _, row := range rows {
+		out := make([]driver.Value, len(indexes))
+		for i, idx := range indexes {
+			out[i] = normalize(row[idx])
+		}
+		result.rows = append(result.rows, out)
+	}
+	return result, nil
+}
+
+type resultRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *resultRows) Columns() []string { return r.columns }
+func (r *resultRows) Close() error      { return nil }
+
+func (r *resultRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, named(args))
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	out := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		out[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return out
+}
+
+func splitList(list string) []string {
+	parts := strings.Split(list, ",")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+	return parts
+}
+
+func indexOf(list []string, value string) int {
+	for i, item := range list {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalize convierte los valores leidos del JSON a los tipos que entiende database/sql.
+func normalize(value any) driver.Value {
+	if number, ok := value.(json.Number); ok {
+		if n, err := number.Int64(); err == nil {
+			return n
+		}
+		f, _ := number.Float64()
+		return f
+	}
+	return value
+}
+
+func equal(a, b any) bool {
+	return fmt.Sprint(normalize(a)) == fmt.Sprint(normalize(b))
+}
+
+func less(a, b any) bool {
+	x, xok := normalize(a).(int64)
+	y, yok := normalize(b).(int64)
+	if xok && yok {
+		return x < y
+	}
+	return fmt.Sprint(normalize(a)) < fmt.Sprint(normalize(b))
+}
@@ -0,0 +1,69 @@
+package filedb
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		"CREATE TABLE IF NOT EXISTS versions (version BIGINT PRIMARY KEY, name TEXT)",
+		"CREATE TABLE IF NOT EXISTS versions (version BIGINT PRIMARY KEY, name TEXT)",
+		"DROP TABLE patentes",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Unexpected error in %q: %v", statement, err)
+		}
+	}
+	for _, version := range []int64{2, 1, 3} {
+		if _, err := db.Exec("INSERT INTO versions (version, name) VALUES (?, ?)", version, "v"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO versions (version, name) VALUES (?, ?)", 1, "again"); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+	if _, err := db.Exec("DELETE FROM versions WHERE version = ?", 3); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// una transaccion descartada no deja cambios
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tx.Exec("INSERT INTO versions (version, name) VALUES (?, ?)", 4, "v")
+	tx.Rollback()
+
+	rows, err := db.Query("SELECT version FROM versions ORDER BY version")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer rows.Close()
+	var versions []int64
+	for rows.Next() {
+		var version int64
+		rows.Scan(&version)
+		versions = append(versions, version)
+	}
+	if !reflect.DeepEqual(versions, []int64{1, 2}) {
+		t.Errorf("Expected versions [1 2], got %v", versions)
+	}
+
+	// las sentencias que no entiende solo se registran
+	_, log, err := State(path)
+	if err != nil || !reflect.DeepEqual(log, []string{"DROP TABLE patentes"}) {
+		t.Errorf("Expected the DROP TABLE in the log, got %v %v", log, err)
+	}
+}
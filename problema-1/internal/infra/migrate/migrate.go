@@ -0,0 +1,322 @@
+// Package migrate aplica migraciones versionadas sobre database/sql.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+
+	defaultTable = "schema_migrations"
+)
+
+var (
+	ErrLocked           = errors.New("migrate: another migration is running")
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+	ErrMissingSource    = errors.New("migrate: applied migration missing from source")
+	ErrNoDown           = errors.New("migrate: migration has no down file")
+)
+
+type Options struct {
+	// Table es la tabla donde se guardan las versiones aplicadas, por defecto schema_migrations.
+	// La tabla del lock se llama igual con el sufijo _lock.
+	Table string
+	// Bindvar formatea el placeholder n-esimo (desde 1) de una query, por defecto "?".
+	Bindvar func(n int) string
+	DryRun  bool
+	Logger  *slog.Logger
+}
+
+type Migrator struct {
+	db      *sql.DB
+	table   string
+	bindvar func(n int) string
+	dryRun  bool
+	logger  *slog.Logger
+}
+
+// Applied es una fila de la tabla de versiones.
+type Applied struct {
+	Version  int64
+	Name     string
+	Checksum string
+}
+
+func New(db *sql.DB, opts Options) *Migrator {
+	m := Migrator{
+		db:      db,
+		table:   opts.Table,
+		bindvar: opts.Bindvar,
+		dryRun:  opts.DryRun,
+		logger:  opts.Logger,
+	}
+	if m.table == "" {
+		m.table = defaultTable
+	}
+	if m.bindvar == nil {
+		m.bindvar = func(int) string { return "?" }
+	}
+	if m.logger == nil {
+		m.logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
+	return &m
+}
+
+// BindvarFor retorna el formato de placeholders que usa el driver indicado.
+func BindvarFor(driverName string) func(n int) string {
+	switch driverName {
+	case "postgres", "pgx":
+		return func(n int) string { return fmt.Sprintf("$%d", n) }
+	}
+	return func(int) string { return "?" }
+}
+
+// Migrate mueve el esquema steps migraciones en la direccion indicada, con steps 0 se aplican
+// (o revierten) todas. Retorna las migraciones ejecutadas, o planificadas en modo dry run.
+func (m *Migrator) Migrate(ctx context.Context, direction string, steps int, migrations []Migration) ([]Migration, error) {
+	if direction != DirectionUp && direction != DirectionDown {
+		return nil, fmt.Errorf("migrate: invalid direction %q, must be up or down", direction)
+	}
+	if steps < 0 {
+		return nil, fmt.Errorf("migrate: steps must be 0 or greater, got %d", steps)
+	}
+
+	// en dry run no se crean las tablas ni se toma el lock, solo se lee lo aplicado
+	if m.dryRun {
+		applied, err := m.appliedIfExists(ctx)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := planMigrations(direction, steps, applied, migrations)
+		if err != nil {
+			return nil, err
+		}
+		for _, migration := range plan {
+			m.logger.Info("Migration planned",
+				slog.String("direction", direction),
+				slog.Int64("version", migration.Version),
+				slog.String("name", migration.Name),
+				slog.Bool("dry_run", true),
+			)
+		}
+		return plan, nil
+	}
+
+	if err := m.ensureTables(ctx); err != nil {
+		return nil, err
+	}
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer m.unlock()
+
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planMigrations(direction, steps, applied, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range plan {
+		start := time.Now()
+		if err := m.apply(ctx, direction, migration); err != nil {
+			return plan[:i], err
+		}
+		m.logger.Info("Migration applied",
+			slog.String("direction", direction),
+			slog.Int64("version", migration.Version),
+			slog.String("name", migration.Name),
+			slog.Duration("duration", time.Since(start)),
+		)
+	}
+	return plan, nil
+}
+
+// appliedIfExists es Applied sin exigir que exista la tabla de versiones: si la consulta falla
+// porque la tabla no existe no hay nada aplicado. Cualquier otro error, como un permiso o una
+// conexion caida, se retorna para no planificar migraciones que ya estan aplicadas.
+func (m *Migrator) appliedIfExists(ctx context.Context) ([]Applied, error) {
+	applied, err := m.Applied(ctx)
+	if err == nil {
+		return applied, nil
+	}
+	if !tableMissing(err) {
+		return nil, err
+	}
+	m.logger.Info("Version table not found, nothing is applied", slog.String("table", m.table))
+	return nil, nil
+}
+
+// tableMissing reporta si err es de una tabla que no existe. No hay un error portable en
+// database/sql: los drivers de postgres exponen el SQLSTATE (42P01, 42S02 en otras bases) y los
+// demas se reconocen por el mensaje, "no such table" en sqlite y "doesn't exist" en mysql.
+func tableMissing(err error) bool {
+	var sqlState interface{ SQLState() string }
+	if errors.As(err, &sqlState) {
+		code := sqlState.SQLState()
+		return code == "42P01" || code == "42S02"
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "no such table") || strings.Contains(msg, "doesn't exist")
+}
+
+// Applied retorna las migraciones registradas en la tabla de versiones ordenadas por version.
+func (m *Migrator) Applied(ctx context.Context) ([]Applied, error) {
+	query := fmt.Sprintf("SELECT version, name, checksum FROM %s ORDER BY version", m.table)
+	rows, err := m.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: reading applied versions: %w", err)
+	}
+	defer rows.Close()
+
+	var applied []Applied
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum); err != nil {
+			return nil, fmt.Errorf("migrate: scanning applied version: %w", err)
+		}
+		applied = append(applied, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate: reading applied versions: %w", err)
+	}
+	return applied, nil
+}
+
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (version BIGINT PRIMARY KEY, name TEXT NOT NULL, checksum TEXT NOT NULL, applied_at TEXT NOT NULL)", m.table),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s_lock (id INTEGER PRIMARY KEY, locked_at TEXT NOT NULL)", m.table),
+	}
+	for _, statement := range statements {
+		if _, err := m.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("migrate: creating version tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// lock usa la clave primaria de la tabla _lock para que solo un proceso pueda insertar la fila.
+func (m *Migrator) lock(ctx context.Context) error {
+	query := fmt.Sprintf("INSERT INTO %s_lock (id, locked_at) VALUES (%s, %s)", m.table, m.bindvar(1), m.bindvar(2))
+	if _, err := m.db.ExecContext(ctx, query, 1, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("%w (if no migration is running delete the row in %s_lock): %v", ErrLocked, m.table, err)
+	}
+	return nil
+}
+
+func (m *Migrator) unlock() {
+	// usamos un contexto nuevo para liberar el lock aunque el contexto original este cancelado
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	query := fmt.Sprintf("DELETE FROM %s_lock WHERE id = %s", m.table, m.bindvar(1))
+	if _, err := m.db.ExecContext(ctx, query, 1); err != nil {
+		m.logger.Error("Failed to release migration lock", slog.String("error", err.Error()))
+	}
+}
+
+func (m *Migrator) apply(ctx context.Context, direction string, migration Migration) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: version %d: begin: %w", migration.Version, err)
+	}
+	defer tx.Rollback()
+
+	body, query, args := migration.Up, "", []any{}
+	if direction == DirectionUp {
+		query = fmt.Sprintf(
+			"INSERT INTO %s (version, name, checksum, applied_at) VALUES (%s, %s, %s, %s)",
+			m.table, m.bindvar(1), m.bindvar(2), m.bindvar(3), m.bindvar(4),
+		)
+		args = append(args, migration.Version, migration.Name, migration.Checksum, time.Now().UTC().Format(time.RFC3339))
+	} else {
+		body = migration.Down
+		query = fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.table, m.bindvar(1))
+		args = append(args, migration.Version)
+	}
+
+	if strings.TrimSpace(body) != "" {
+		if _, err := tx.ExecContext(ctx, body); err != nil {
+			return fmt.Errorf("migrate: version %d %s: %w", migration.Version, direction, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("migrate: version %d: recording version: %w", migration.Version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate: version %d: commit: %w", migration.Version, err)
+	}
+	return nil
+}
+
+// verify revisa que cada version aplicada siga existiendo en los archivos y que su contenido no
+// haya cambiado despues de aplicarla.
+func verify(applied []Applied, migrations []Migration) error {
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+	for _, a := range applied {
+		migration, ok := byVersion[a.Version]
+		if !ok {
+			return fmt.Errorf("%w: version %d (%s)", ErrMissingSource, a.Version, a.Name)
+		}
+		if migration.Checksum != a.Checksum {
+			return fmt.Errorf("%w: version %d (%s) was modified after being applied", ErrChecksumMismatch, a.Version, a.Name)
+		}
+	}
+	return nil
+}
+
+// planMigrations verifica las versiones aplicadas y retorna las migraciones a ejecutar en orden.
+func planMigrations(direction string, steps int, applied []Applied, migrations []Migration) ([]Migration, error) {
+	if err := verify(applied, migrations); err != nil {
+		return nil, err
+	}
+	plan := planFor(direction, steps, applied, migrations)
+	for _, migration := range plan {
+		if direction == DirectionDown && migration.Down == "" {
+			return nil, fmt.Errorf("%w: version %d", ErrNoDown, migration.Version)
+		}
+	}
+	return plan, nil
+}
+
+func planFor(direction string, steps int, applied []Applied, migrations []Migration) []Migration {
+	isApplied := make(map[int64]bool, len(applied))
+	for _, a := range applied {
+		isApplied[a.Version] = true
+	}
+
+	var plan []Migration
+	if direction == DirectionUp {
+		for _, migration := range migrations {
+			if !isApplied[migration.Version] {
+				plan = append(plan, migration)
+			}
+		}
+	} else {
+		// para bajar recorremos desde la ultima version aplicada hacia atras
+		for i := len(migrations) - 1; i >= 0; i-- {
+			if isApplied[migrations[i].Version] {
+				plan = append(plan, migrations[i])
+			}
+		}
+	}
+
+	if steps > 0 && steps < len(plan) {
+		plan = plan[:steps]
+	}
+	return plan
+}
@@ -0,0 +1,262 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/do-prueba-tecnica/problema-1/internal/infra/migrate/internal/filedb"
+)
+
+func testSource() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/0001_patentes.up.sql":    {Data: []byte("CREATE TABLE patentes (id BIGINT PRIMARY KEY, patente TEXT)")},
+		"migrations/0001_patentes.down.sql":  {Data: []byte("DROP TABLE patentes")},
+		"migrations/0002_indice.up.sql":      {Data: []byte("CREATE INDEX patentes_patente ON patentes (patente)")},
+		"migrations/0002_indice.down.sql":    {Data: []byte("DROP INDEX patentes_patente")},
+		"migrations/0003_consultas.up.sql":   {Data: []byte("CREATE TABLE consultas (id BIGINT PRIMARY KEY, ruta TEXT)")},
+		"migrations/0003_consultas.down.sql": {Data: []byte("DROP TABLE consultas")},
+		"migrations/README.md":               {Data: []byte("ignored")},
+	}
+}
+
+func setupDB(t *testing.T) (*sql.DB, string) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	db, err := sql.Open(filedb.DriverName, path)
+	if err != nil {
+		t.Fatalf("Failed to open filedb: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, path
+}
+
+func versions(t *testing.T, m *Migrator) []int64 {
+	applied, err := m.Applied(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to read applied versions: %v", err)
+	}
+	var out []int64
+	for _, a := range applied {
+		out = append(out, a.Version)
+	}
+	return out
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load(testSource(), "migrations")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("Expected 3 migrations, got %d", len(migrations))
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("Expected version %d, got %d", i+1, m.Version)
+		}
+		if len(m.Checksum) != 64 {
+			t.Errorf("Expected sha256 checksum, got %q", m.Checksum)
+		}
+	}
+
+	_, err = Load(fstest.MapFS{
+		"migrations/0001_a.down.sql": {Data: []byte("DROP TABLE a")},
+	}, "migrations")
+	if err == nil {
+		t.Errorf("Expected error for migration without up file, got nil")
+	}
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db, path := setupDB(t)
+	m := New(db, Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	migrations, err := Load(testSource(), "migrations")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		direction string
+		steps     int
+		expected  []int64
+	}{
+		{"up one step", DirectionUp, 1, []int64{1}},
+		{"up all", DirectionUp, 0, []int64{1, 2, 3}},
+		{"up again is noop", DirectionUp, 0, []int64{1, 2, 3}},
+		{"down two steps", DirectionDown, 2, []int64{1}},
+		{"down all", DirectionDown, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.Migrate(ctx, tt.direction, tt.steps, migrations); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got := versions(t, m)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("Expected versions %v, got %v", tt.expected, got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("Expected versions %v, got %v", tt.expected, got)
+				}
+			}
+		})
+	}
+
+	_, log, err := filedb.State(path)
+	if err != nil {
+		t.Fatalf("Failed to read state: %v", err)
+	}
+	// CREATE TABLE se interpreta, el resto de sentencias queda en el log
+	expectedLog := []string{
+		"CREATE INDEX patentes_patente ON patentes (patente)",
+		"DROP TABLE consultas",
+		"DROP INDEX patentes_patente",
+		"DROP TABLE patentes",
+	}
+	if len(log) != len(expectedLog) {
+		t.Fatalf("Expected log %v, got %v", expectedLog, log)
+	}
+	for i := range log {
+		if log[i] != expectedLog[i] {
+			t.Errorf("Expected statement %q, got %q", expectedLog[i], log[i])
+		}
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	ctx := context.Background()
+	db, path := setupDB(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	m := New(db, Options{DryRun: true, Logger: logger})
+	migrations, _ := Load(testSource(), "migrations")
+
+	plan, err := m.Migrate(ctx, DirectionUp, 0, migrations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plan) != 3 {
+		t.Errorf("Expected 3 planned migrations, got %d", len(plan))
+	}
+	// dry run no crea las tablas de versiones ni toma el lock
+	if tables, log, _ := filedb.State(path); len(tables) != 0 || len(log) != 0 {
+		t.Errorf("Dry run must not change the database, got tables %v and log %v", tables, log)
+	}
+
+	if _, err := New(db, Options{Logger: logger}).Migrate(ctx, DirectionUp, 1, migrations); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// con otra migracion en curso el dry run igual puede planificar
+	if _, err := db.Exec("INSERT INTO schema_migrations_lock (id, locked_at) VALUES (?, ?)", 1, "now"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	plan, err = m.Migrate(ctx, DirectionUp, 0, migrations)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plan) != 2 || plan[0].Version != 2 {
+		t.Errorf("Expected versions 2 and 3 planned, got %v", plan)
+	}
+	if got := versions(t, m); len(got) != 1 {
+		t.Errorf("Dry run must not apply migrations, got versions %v", got)
+	}
+}
+
+// sqlStateError imita los errores de los drivers de postgres.
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "pq: error " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestTableMissing(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"postgres undefined table", fmt.Errorf("migrate: reading applied versions: %w", sqlStateError("42P01")), true},
+		{"postgres permission denied", sqlStateError("42501"), false},
+		{"sqlite", errors.New("no such table: schema_migrations"), true},
+		{"mysql", errors.New("Error 1146 (42S02): Table 'db.schema_migrations' doesn't exist"), true},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tableMissing(tt.err); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMigrateDryRunReadError(t *testing.T) {
+	db, path := setupDB(t)
+	if err := os.WriteFile(path, []byte("{corrupt"), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := New(db, Options{DryRun: true, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	migrations, _ := Load(testSource(), "migrations")
+
+	// una base que no se puede leer no es una base sin migraciones
+	if plan, err := m.Migrate(context.Background(), DirectionUp, 0, migrations); err == nil {
+		t.Errorf("Expected the read error, got plan %v", plan)
+	}
+}
+
+func TestMigrateChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	db, _ := setupDB(t)
+	m := New(db, Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	source := testSource()
+	migrations, _ := Load(source, "migrations")
+	if _, err := m.Migrate(ctx, DirectionUp, 0, migrations); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	source["migrations/0002_indice.up.sql"] = &fstest.MapFile{Data: []byte("CREATE INDEX otro ON patentes (id)")}
+	modified, _ := Load(source, "migrations")
+	_, err := m.Migrate(ctx, DirectionUp, 0, modified)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+
+	_, err = m.Migrate(ctx, DirectionUp, 0, migrations[:1])
+	if !errors.Is(err, ErrMissingSource) {
+		t.Errorf("Expected ErrMissingSource, got %v", err)
+	}
+}
+
+func TestMigrateLocked(t *testing.T) {
+	ctx := context.Background()
+	db, _ := setupDB(t)
+	m := New(db, Options{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	migrations, _ := Load(testSource(), "migrations")
+
+	// simulamos otro proceso que tiene tomado el lock
+	if err := m.ensureTables(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := m.lock(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err := m.Migrate(ctx, DirectionUp, 0, migrations)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Expected ErrLocked, got %v", err)
+	}
+
+	m.unlock()
+	if _, err := m.Migrate(ctx, DirectionUp, 0, migrations); err != nil {
+		t.Errorf("Unexpected error after unlock: %v", err)
+	}
+}
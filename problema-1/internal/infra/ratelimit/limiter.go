@@ -0,0 +1,116 @@
+// Package ratelimit limita los requests por cliente con token buckets en memoria y cuotas diarias
+// que se guardan en disco para sobrevivir reinicios.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Decision es el resultado de consumir un token.
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset es el tiempo hasta que el bucket vuelve a estar lleno, o hasta que haya un token si el
+	// request fue rechazado.
+	Reset time.Duration
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter tiene un token bucket por cliente que se rellena a rate tokens por segundo hasta burst.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Allow consume un token del bucket de key.
+func (l *Limiter) Allow(key string, now time.Time) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	l.refill(b, now)
+
+	d := Decision{Limit: int(l.burst)}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+		d.Reset = l.duration(l.burst - b.tokens)
+	} else {
+		d.Reset = l.duration(1 - b.tokens)
+	}
+	d.Remaining = int(math.Floor(b.tokens))
+	return d
+}
+
+// Blocked reporta si al bucket de key no le quedan tokens sin consumir uno, y cuanto falta para el
+// proximo. Sirve para rechazar antes de hacer el trabajo que se limita.
+func (l *Limiter) Blocked(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return 0, false
+	}
+	l.refill(b, now)
+	if b.tokens >= 1 {
+		return 0, false
+	}
+	return l.duration(1 - b.tokens), true
+}
+
+func (l *Limiter) refill(b *bucket, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rate)
+		b.last = now
+	}
+}
+
+func (l *Limiter) duration(tokens float64) time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / l.rate * float64(time.Second))
+}
+
+// sweep borra cada minuto los buckets que ya se llenaron, un cliente inactivo no ocupa memoria y
+// si vuelve parte con el bucket lleno igual que si se hubiera conservado.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const dayLayout = "2006-01-02"
+
+// Quota cuenta los requests diarios por cliente, el dia cambia a medianoche UTC. Los contadores se
+// mantienen en memoria y Flush los escribe en el archivo.
+type Quota struct {
+	path  string
+	limit int64
+
+	mu     sync.Mutex
+	day    string
+	counts map[string]int64
+	dirty  bool
+}
+
+type quotaFile struct {
+	Day    string           `json:"day"`
+	Counts map[string]int64 `json:"counts"`
+}
+
+// OpenQuota carga los contadores del archivo si son del dia de hoy.
+func OpenQuota(path string, limit int64, now time.Time) (*Quota, error) {
+	q := &Quota{
+		path:   path,
+		limit:  limit,
+		day:    now.UTC().Format(dayLayout),
+		counts: map[string]int64{},
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: reading %q: %w", path, err)
+	}
+	var f quotaFile
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("ratelimit: decoding %q: %w", path, err)
+	}
+	if f.Day == q.day && f.Counts != nil {
+		q.counts = f.Counts
+	}
+	return q, nil
+}
+
+// Allow suma un request al cliente y retorna cuantos le quedan hoy, si ya no le quedan el request no
+// se cuenta.
+func (q *Quota) Allow(key string, now time.Time) (int64, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if day := now.UTC().Format(dayLayout); day != q.day {
+		q.day = day
+		q.counts = map[string]int64{}
+		q.dirty = true
+	}
+	if q.counts[key] >= q.limit {
+		return 0, false
+	}
+	q.counts[key]++
+	q.dirty = true
+	return q.limit - q.counts[key], true
+}
+
+// Limit retorna la cantidad de requests diarios permitidos.
+func (q *Quota) Limit() int64 {
+	return q.limit
+}
+
+// UntilReset retorna el tiempo que falta para la medianoche UTC.
+func UntilReset(now time.Time) time.Duration {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return midnight.Sub(now)
+}
+
+// Flush escribe los contadores si cambiaron desde la ultima vez.
+func (q *Quota) Flush() error {
+	err := q.flush()
+	if err != nil {
+		// se vuelve a intentar en el siguiente Flush
+		q.mu.Lock()
+		q.dirty = true
+		q.mu.Unlock()
+	}
+	return err
+}
+
+func (q *Quota) flush() error {
+	q.mu.Lock()
+	if !q.dirty {
+		q.mu.Unlock()
+		return nil
+	}
+	f := quotaFile{Day: q.day, Counts: make(map[string]int64, len(q.counts))}
+	for key, count := range q.counts {
+		f.Counts[key] = count
+	}
+	q.dirty = false
+	q.mu.Unlock()
+
+	content, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("ratelimit: encoding quota: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(q.path), ".quota-*")
+	if err != nil {
+		return fmt.Errorf("ratelimit: writing %q: %w", q.path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ratelimit: writing %q: %w", q.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ratelimit: writing %q: %w", q.path, err)
+	}
+	if err := os.Rename(tmp.Name(), q.path); err != nil {
+		return fmt.Errorf("ratelimit: writing %q: %w", q.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(1, 3)
+	now := time.Now()
+
+	for i := 2; i >= 0; i-- {
+		d := l.Allow("a", now)
+		if !d.Allowed || d.Remaining != i || d.Limit != 3 {
+			t.Fatalf("Expected allowed with %d remaining, got %+v", i, d)
+		}
+	}
+	d := l.Allow("a", now)
+	if d.Allowed {
+		t.Fatalf("Expected request over burst to be rejected, got %+v", d)
+	}
+	if d.Reset != time.Second {
+		t.Errorf("Expected reset of 1s until next token, got %v", d.Reset)
+	}
+
+	if d := l.Allow("b", now); !d.Allowed {
+		t.Errorf("Expected other client to have its own bucket")
+	}
+
+	if d := l.Allow("a", now.Add(1500*time.Millisecond)); !d.Allowed {
+		t.Errorf("Expected a token after 1.5s, got %+v", d)
+	}
+
+	// despues de un minuto los buckets llenos se eliminan
+	l.Allow("c", now.Add(2*time.Minute))
+	if len(l.buckets) != 1 {
+		t.Errorf("Expected idle buckets to be swept, got %d buckets", len(l.buckets))
+	}
+}
+
+func TestLimiterBlocked(t *testing.T) {
+	l := NewLimiter(1, 2)
+	now := time.Now()
+
+	if _, blocked := l.Blocked("a", now); blocked {
+		t.Fatalf("Expected a new client not blocked")
+	}
+	l.Allow("a", now)
+	l.Allow("a", now)
+	wait, blocked := l.Blocked("a", now)
+	if !blocked || wait != time.Second {
+		t.Fatalf("Expected blocked for 1s, got %v %v", wait, blocked)
+	}
+	// Blocked no consume tokens
+	if _, blocked := l.Blocked("a", now.Add(time.Second)); blocked {
+		t.Errorf("Expected a token after 1s")
+	}
+	if d := l.Allow("a", now.Add(time.Second)); !d.Allowed {
+		t.Errorf("Expected the token unused by Blocked, got %+v", d)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	now := time.Date(2024, 5, 10, 23, 59, 0, 0, time.UTC)
+
+	q, err := OpenQuota(path, 2, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining, ok := q.Allow("a", now); !ok || remaining != 1 {
+		t.Fatalf("Expected 1 remaining, got %d %v", remaining, ok)
+	}
+	if err := q.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// al reiniciar se conserva lo consumido en el dia
+	q, err = OpenQuota(path, 2, now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if remaining, ok := q.Allow("a", now); !ok || remaining != 0 {
+		t.Fatalf("Expected 0 remaining after restart, got %d %v", remaining, ok)
+	}
+	if _, ok := q.Allow("a", now); ok {
+		t.Fatalf("Expected quota to be exhausted")
+	}
+	if UntilReset(now) != time.Minute {
+		t.Errorf("Expected reset in 1m, got %v", UntilReset(now))
+	}
+
+	// al cambiar el dia la cuota parte de nuevo
+	if _, ok := q.Allow("a", now.Add(2*time.Minute)); !ok {
+		t.Errorf("Expected quota to reset on a new day")
+	}
+}
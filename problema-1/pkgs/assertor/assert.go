@@ -12,8 +12,18 @@ func ErrNil(err error, msg string) {
 	}
 }
 
+// AssertionError es el valor con el que hacen panic las funciones del paquete, permite distinguir
+// una asercion fallida de otros panics al recuperarlos.
+type AssertionError struct {
+	Msg string
+}
+
+func (e AssertionError) Error() string {
+	return e.Msg
+}
+
 func assert(condition bool, msg string) {
 	if !condition {
-		panic(msg)
+		panic(AssertionError{Msg: msg})
 	}
 }
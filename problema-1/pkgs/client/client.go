@@ -0,0 +1,264 @@
+// Package client es el cliente Go de la api de patentes, con reintentos para los errores
+// transitorios y errores tipados con los mismos codigos que responde el servidor.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetries    = 3
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+	// maxErrorBody acota lo que se lee de una respuesta de error
+	maxErrorBody = 64 << 10
+)
+
+// Converter es lo que ofrece la api, Client y Mock lo implementan. Los consumidores deberian
+// depender de Converter para poder usar Mock en sus tests.
+type Converter interface {
+	// PatentFromID retorna la patente del id, los ids validos empiezan en 1.
+	PatentFromID(ctx context.Context, id uint) (string, error)
+	// IDFromPatent retorna el id de una patente con formato LLLLNNN.
+	IDFromPatent(ctx context.Context, patente string) (uint, error)
+}
+
+var _ Converter = (*Client)(nil)
+
+// Client llama a la api por HTTP, es seguro usarlo desde varias goroutines.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	token      string
+	userAgent  string
+
+	retries    int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configura un Client en New.
+type Option func(*Client)
+
+// WithHTTPClient reemplaza el http.Client, por defecto se usa uno con timeout de 30 segundos.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithTransport reemplaza el transporte del http.Client, por ejemplo para usar TLS con certificado
+// de cliente o instrumentar los requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		httpClient := *c.httpClient
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithAPIKey envia la api key en X-API-Key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithBearerToken envia un JWT en Authorization.
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithUserAgent cambia el User-Agent, sirve para identificar al consumidor en los logs del servidor.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) { c.userAgent = userAgent }
+}
+
+// WithRetries configura cuantas veces se reintenta un request y la espera entre intentos, que
+// crece exponencialmente desde min hasta max con jitter. Con retries 0 no se reintenta.
+func WithRetries(retries int, min, max time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// New crea un cliente para la api en baseURL, por ejemplo https://patentes.example.com.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("client: invalid base url %q", baseURL)
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(u.String(), "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "patentes-go-client",
+		retries:    defaultRetries,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.retries < 0 || c.minBackoff < 0 || c.maxBackoff < c.minBackoff {
+		return nil, errors.New("client: invalid retries configuration")
+	}
+	return c, nil
+}
+
+// PatentFromID llama a GET /v1/patente/{id}.
+func (c *Client) PatentFromID(ctx context.Context, id uint) (string, error) {
+	var resp struct {
+		Patente string `json:"patente"`
+	}
+	if err := c.get(ctx, "/v1/patente/"+strconv.FormatUint(uint64(id), 10), &resp); err != nil {
+		return "", err
+	}
+	return resp.Patente, nil
+}
+
+// IDFromPatent llama a GET /v1/id/{patente}.
+func (c *Client) IDFromPatent(ctx context.Context, patente string) (uint, error) {
+	var resp struct {
+		ID uint `json:"id"`
+	}
+	if err := c.get(ctx, "/v1/id/"+url.PathEscape(patente), &resp); err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+// get hace un GET con reintentos, los GET de la api son idempotentes.
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	for attempt := 0; ; attempt++ {
+		err := c.do(ctx, c.baseURL+path, out)
+		if err == nil {
+			return nil
+		}
+		wait, retry := c.backoff(err, attempt)
+		if !retry {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, target string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return parseError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("client: invalid response: %w", err)
+	}
+	return nil
+}
+
+// backoff indica si err se puede reintentar y cuanto esperar. Se reintentan los errores de red,
+// 429 y 502, 503 y 504, los demas no van a cambiar con otro intento.
+func (c *Client) backoff(err error, attempt int) (time.Duration, bool) {
+	if attempt >= c.retries {
+		return 0, false
+	}
+	var transportErr *transportError
+	var apiErr *Error
+	switch {
+	case errors.As(err, &transportErr):
+		// un context cancelado tambien llega como error de red
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+	case errors.As(err, &apiErr):
+		if !apiErr.Temporary() {
+			return 0, false
+		}
+		if apiErr.RetryAfter > 0 {
+			// una cuota agotada puede pedir esperar horas, mejor retornar el error
+			if apiErr.RetryAfter > c.maxBackoff {
+				return 0, false
+			}
+			return apiErr.RetryAfter, true
+		}
+	default:
+		return 0, false
+	}
+
+	// full jitter: un valor al azar hasta min * 2^attempt, acotado por max
+	ceiling := c.minBackoff << attempt
+	if ceiling > c.maxBackoff || ceiling <= 0 {
+		ceiling = c.maxBackoff
+	}
+	if ceiling <= 0 {
+		return 0, true
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1)), true
+}
+
+// transportError es un error de red, el request pudo no llegar al servidor.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return "client: " + e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// parseError lee el problem JSON de una respuesta de error, si el body no es un problem, por
+// ejemplo el de un proxy, el error queda solo con el status.
+func parseError(resp *http.Response) error {
+	var body struct {
+		Code          string `json:"code"`
+		Detail        string `json:"detail"`
+		CorrelationID string `json:"correlation_id"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&body)
+	apiErr := &Error{
+		Status:    resp.StatusCode,
+		Code:      body.Code,
+		Detail:    body.Detail,
+		RequestID: body.CorrelationID,
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-ID")
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiErr
+}
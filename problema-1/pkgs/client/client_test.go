@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		retryAfter    string
+		expectedCalls int32
+		expectedErr   error
+	}{
+		{"retries unavailable", []int{503, 503, 200}, "", 3, nil},
+		{"retries rate limited", []int{429, 200}, "1", 2, nil},
+		{"gives up after the retries", []int{502, 502, 502, 502}, "", 4, &Error{Code: ""}},
+		{"does not retry client errors", []int{400, 200}, "", 1, ErrBadFormat},
+		{"does not wait for a long Retry-After", []int{429, 200}, "3600", 1, ErrRateLimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls.Add(1)-1]
+				if status == http.StatusOK {
+					w.Write([]byte(`{"patente": "AAAA000"}`))
+					return
+				}
+				code := CodeBadFormat
+				if status == http.StatusTooManyRequests {
+					code = CodeRateLimited
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				if status >= 500 {
+					code = ""
+				}
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.WriteHeader(status)
+				w.Write([]byte(`{"code": "` + code + `", "correlation_id": "abc"}`))
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, WithRetries(3, time.Millisecond, 2*time.Second))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			patente, err := c.PatentFromID(context.Background(), 1)
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls.Load())
+			}
+			if tt.expectedErr == nil {
+				if err != nil || patente != "AAAA000" {
+					t.Errorf("Expected AAAA000, got %q %v", patente, err)
+				}
+				return
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.RequestID != "abc" {
+				t.Fatalf("Expected an *Error with the request id, got %v", err)
+			}
+			if code := tt.expectedErr.(*Error).Code; code != "" && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := New(server.URL, WithRetries(10, time.Second, time.Second))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.IDFromPatent(ctx, "AAAA000"); err == nil {
+		t.Fatal("Expected an error")
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected the retries to stop with the context, took %s", time.Since(start))
+	}
+}
+
+func TestMock(t *testing.T) {
+	var conv Converter = &Mock{
+		IDFromPatentFunc: func(ctx context.Context, patente string) (uint, error) {
+			return 0, ErrBadFormat
+		},
+	}
+	if _, err := conv.IDFromPatent(context.Background(), "x"); !errors.Is(err, ErrBadFormat) {
+		t.Errorf("Expected ErrBadFormat, got %v", err)
+	}
+	if _, err := conv.PatentFromID(context.Background(), 1); err == nil {
+		t.Error("Expected an error without PatentFromIDFunc")
+	}
+	calls := conv.(*Mock).Calls()
+	if len(calls) != 2 || calls[0] != (Call{"IDFromPatent", "x"}) || calls[1] != (Call{"PatentFromID", uint(1)}) {
+		t.Errorf("Unexpected calls %v", calls)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "ftp://example.com"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("Expected an error for %q", baseURL)
+		}
+	}
+}
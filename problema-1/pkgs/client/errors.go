@@ -0,0 +1,80 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Codigos de error que responde el servidor en el campo code del problem JSON.
+const (
+	// errores de las conversiones
+	CodeInvalidRange = "invalid_range"
+	CodeEmpty        = "empty"
+	CodeBadFormat    = "bad_format"
+	CodeInvalidID    = "invalid_id"
+
+	// errores de autenticacion y limites
+	CodeUnauthorized  = "unauthorized"
+	CodeForbidden     = "forbidden"
+	CodeRateLimited   = "rate_limited"
+	CodeQuotaExceeded = "quota_exceeded"
+
+	CodeNotFound = "not_found"
+	CodeInternal = "internal_error"
+)
+
+// Errores para comparar con errors.Is, comparan solo el codigo:
+//
+//	if errors.Is(err, client.ErrBadFormat) { ... }
+var (
+	ErrInvalidRange  = &Error{Code: CodeInvalidRange}
+	ErrEmpty         = &Error{Code: CodeEmpty}
+	ErrBadFormat     = &Error{Code: CodeBadFormat}
+	ErrInvalidID     = &Error{Code: CodeInvalidID}
+	ErrUnauthorized  = &Error{Code: CodeUnauthorized}
+	ErrForbidden     = &Error{Code: CodeForbidden}
+	ErrRateLimited   = &Error{Code: CodeRateLimited}
+	ErrQuotaExceeded = &Error{Code: CodeQuotaExceeded}
+	ErrNotFound      = &Error{Code: CodeNotFound}
+	ErrInternal      = &Error{Code: CodeInternal}
+)
+
+// Error es una respuesta de error de la api.
+type Error struct {
+	// Status es el status HTTP, 0 en los errores de Mock
+	Status int
+	// Code es el codigo estable del error, vacio si la respuesta no era un problem JSON
+	Code   string
+	Detail string
+	// RequestID es el X-Request-ID del request, sirve para buscarlo en los logs del servidor
+	RequestID string
+	// RetryAfter es la espera que pidio el servidor en un 429 o 503
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	code := e.Code
+	if code == "" {
+		code = http.StatusText(e.Status)
+	}
+	if e.Detail != "" {
+		return fmt.Sprintf("client: %s: %s", code, e.Detail)
+	}
+	return fmt.Sprintf("client: %s", code)
+}
+
+// Is compara por codigo, asi errors.Is funciona con los Err de este paquete.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// Temporary indica si el mismo request puede funcionar mas tarde.
+func (e *Error) Temporary() bool {
+	switch e.Status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
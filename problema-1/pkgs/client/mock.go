@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var _ Converter = (*Mock)(nil)
+
+// Mock implementa Converter para los tests de los consumidores. Las funciones definen la respuesta
+// y Calls registra las llamadas:
+//
+//	mock := &client.Mock{
+//		PatentFromIDFunc: func(ctx context.Context, id uint) (string, error) {
+//			return "", client.ErrInvalidRange
+//		},
+//	}
+type Mock struct {
+	PatentFromIDFunc func(ctx context.Context, id uint) (string, error)
+	IDFromPatentFunc func(ctx context.Context, patente string) (uint, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Call es una llamada al Mock, Arg es el id o la patente.
+type Call struct {
+	Method string
+	Arg    any
+}
+
+// PatentFromID llama a PatentFromIDFunc, sin funcion retorna un error.
+func (m *Mock) PatentFromID(ctx context.Context, id uint) (string, error) {
+	m.record("PatentFromID", id)
+	if m.PatentFromIDFunc == nil {
+		return "", errors.New("client: mock without PatentFromIDFunc")
+	}
+	return m.PatentFromIDFunc(ctx, id)
+}
+
+// IDFromPatent llama a IDFromPatentFunc, sin funcion retorna un error.
+func (m *Mock) IDFromPatent(ctx context.Context, patente string) (uint, error) {
+	m.record("IDFromPatent", patente)
+	if m.IDFromPatentFunc == nil {
+		return 0, errors.New("client: mock without IDFromPatentFunc")
+	}
+	return m.IDFromPatentFunc(ctx, patente)
+}
+
+// Calls retorna las llamadas recibidas en orden.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *Mock) record(method string, arg any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Arg: arg})
+}